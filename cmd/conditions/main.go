@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//
+// Report the conditions of kubernetes objects found in
+// YAML and JSON files (manifests and must-gather dumps).
+//
+// Usage:
+//     conditions [-o text|json|markdown] [-category Error,Critical] <path> ...
+//
+func main() {
+	format := flag.String("o", Text, "Output format: text|json|markdown.")
+	category := flag.String("category", "", "Comma separated list of categories.")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] <path> ...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	report := Report{}
+	if *category != "" {
+		report.Categories = strings.Split(*category, ",")
+	}
+	err := report.Load(flag.Args()...)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	err = report.Write(os.Stdout, *format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
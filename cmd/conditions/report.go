@@ -0,0 +1,340 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/jortel/controller/pkg/condition"
+	"io"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats.
+const (
+	Text     = "text"
+	JSON     = "json"
+	Markdown = "markdown"
+)
+
+// File extensions.
+var Extensions = map[string]bool{
+	".yaml": true,
+	".yml":  true,
+	".json": true,
+}
+
+//
+// A kubernetes object (document).
+// Only the fields needed for reporting.
+type object struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Namespace string `json:"namespace"`
+		Name      string `json:"name"`
+	} `json:"metadata"`
+	Status struct {
+		Conditions []condition.Condition `json:"conditions"`
+	} `json:"status"`
+	Items []json.RawMessage `json:"items"`
+}
+
+//
+// A resource and its conditions.
+type Resource struct {
+	Kind       string                `json:"kind"`
+	Namespace  string                `json:"namespace,omitempty"`
+	Name       string                `json:"name"`
+	Source     string                `json:"source"`
+	Conditions []condition.Condition `json:"conditions"`
+	// All (unfiltered) conditions.
+	all []condition.Condition
+}
+
+//
+// Blocker summary.
+// The number of resources with a blocker condition by type.
+type Blocker struct {
+	Type     string `json:"type"`
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+//
+// Condition report.
+type Report struct {
+	// Category filter.
+	Categories []string `json:"-"`
+	// Resources.
+	Resources []Resource `json:"resources"`
+	// Blocker summary.
+	Blockers []Blocker `json:"blockers"`
+}
+
+//
+// Load files and directories.
+func (r *Report) Load(paths ...string) error {
+	for _, path := range paths {
+		err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || !Extensions[strings.ToLower(filepath.Ext(path))] {
+				return nil
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return r.Read(path, f)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//
+// Read (YAML or JSON) documents.
+func (r *Report) Read(source string, reader io.Reader) error {
+	decoder := yaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		raw := json.RawMessage{}
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %s", source, err)
+		}
+		if len(bytes.TrimSpace(raw)) == 0 || string(raw) == "null" {
+			continue
+		}
+		err = r.add(source, raw)
+		if err != nil {
+			return fmt.Errorf("%s: %s", source, err)
+		}
+	}
+
+	return nil
+}
+
+//
+// Add the resource (document).
+// Lists are expanded.
+func (r *Report) add(source string, raw json.RawMessage) error {
+	obj := object{}
+	err := json.Unmarshal(raw, &obj)
+	if err != nil {
+		return err
+	}
+	for _, item := range obj.Items {
+		err = r.add(source, item)
+		if err != nil {
+			return err
+		}
+	}
+	if obj.Items != nil || obj.Metadata.Name == "" {
+		return nil
+	}
+	resource := Resource{
+		Kind:       obj.Kind,
+		Namespace:  obj.Metadata.Namespace,
+		Name:       obj.Metadata.Name,
+		Source:     source,
+		Conditions: []condition.Condition{},
+	}
+	for _, cnd := range obj.Status.Conditions {
+		cnd.BuildItems()
+		resource.all = append(resource.all, cnd)
+		if r.matched(cnd) {
+			resource.Conditions = append(resource.Conditions, cnd)
+		}
+	}
+	r.Resources = append(r.Resources, resource)
+	return nil
+}
+
+//
+// Get whether the condition matches the category filter.
+func (r *Report) matched(cnd condition.Condition) bool {
+	if len(r.Categories) == 0 {
+		return true
+	}
+	for _, name := range r.Categories {
+		if strings.EqualFold(name, cnd.Category) {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// Summarize blocker conditions.
+// The category filter is not applied.
+func (r *Report) Summarize() {
+	counts := map[Blocker]int{}
+	for _, resource := range r.Resources {
+		all := resource.all
+		if all == nil {
+			all = resource.Conditions
+		}
+		for _, cnd := range all {
+			if cnd.Status != condition.True {
+				continue
+			}
+			if cnd.Category != condition.Critical && cnd.Category != condition.Error {
+				continue
+			}
+			counts[Blocker{Type: cnd.Type, Category: cnd.Category}]++
+		}
+	}
+	r.Blockers = []Blocker{}
+	for blocker, count := range counts {
+		blocker.Count = count
+		r.Blockers = append(r.Blockers, blocker)
+	}
+	sort.Slice(r.Blockers, func(i, j int) bool {
+		a, b := r.Blockers[i], r.Blockers[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Type < b.Type
+	})
+}
+
+//
+// Write the report in the specified format.
+func (r *Report) Write(out io.Writer, format string) error {
+	r.Summarize()
+	switch format {
+	case Text:
+		r.text(out)
+	case JSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r.expanded())
+	case Markdown:
+		r.markdown(out)
+	default:
+		return fmt.Errorf("format: %s not supported", format)
+	}
+
+	return nil
+}
+
+//
+// Copy of the report with condition items expanded
+// into the messages. The `Items` are not encoded.
+func (r *Report) expanded() *Report {
+	report := *r
+	report.Resources = []Resource{}
+	for _, resource := range r.Resources {
+		conditions := []condition.Condition{}
+		for _, cnd := range resource.Conditions {
+			cnd.Message = r.message(cnd)
+			conditions = append(conditions, cnd)
+		}
+		resource.Conditions = conditions
+		report.Resources = append(report.Resources, resource)
+	}
+
+	return &report
+}
+
+//
+// Write text.
+func (r *Report) text(out io.Writer) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, resource := range r.Resources {
+		fmt.Fprintf(w, "%s %s (%s)\n", resource.Kind, r.name(resource), resource.Source)
+		if len(resource.Conditions) == 0 {
+			fmt.Fprintln(w, "  (none)")
+			fmt.Fprintln(w)
+			continue
+		}
+		fmt.Fprintln(w, "  TYPE\tSTATUS\tCATEGORY\tREASON\tMESSAGE")
+		for _, cnd := range resource.Conditions {
+			fmt.Fprintf(
+				w,
+				"  %s\t%s\t%s\t%s\t%s\n",
+				cnd.Type,
+				cnd.Status,
+				cnd.Category,
+				cnd.Reason,
+				r.message(cnd))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, "BLOCKERS")
+	if len(r.Blockers) == 0 {
+		fmt.Fprintln(w, "  (none)")
+	} else {
+		fmt.Fprintln(w, "  TYPE\tCATEGORY\tRESOURCES")
+		for _, blocker := range r.Blockers {
+			fmt.Fprintf(w, "  %s\t%s\t%d\n", blocker.Type, blocker.Category, blocker.Count)
+		}
+	}
+	w.Flush()
+}
+
+//
+// Write markdown.
+func (r *Report) markdown(out io.Writer) {
+	for _, resource := range r.Resources {
+		fmt.Fprintf(out, "## %s %s\n\n", resource.Kind, r.name(resource))
+		fmt.Fprintf(out, "Source: `%s`\n\n", resource.Source)
+		if len(resource.Conditions) == 0 {
+			fmt.Fprint(out, "_No conditions._\n\n")
+			continue
+		}
+		fmt.Fprintln(out, "| Type | Status | Category | Reason | Message |")
+		fmt.Fprintln(out, "|------|--------|----------|--------|---------|")
+		for _, cnd := range resource.Conditions {
+			fmt.Fprintf(
+				out,
+				"| %s | %s | %s | %s | %s |\n",
+				cnd.Type,
+				cnd.Status,
+				cnd.Category,
+				cnd.Reason,
+				strings.Replace(r.message(cnd), "|", "\\|", -1))
+		}
+		fmt.Fprintln(out)
+	}
+	fmt.Fprint(out, "## Blockers\n\n")
+	if len(r.Blockers) == 0 {
+		fmt.Fprint(out, "_None._\n")
+		return
+	}
+	fmt.Fprintln(out, "| Type | Category | Resources |")
+	fmt.Fprintln(out, "|------|----------|-----------|")
+	for _, blocker := range r.Blockers {
+		fmt.Fprintf(out, "| %s | %s | %d |\n", blocker.Type, blocker.Category, blocker.Count)
+	}
+}
+
+//
+// Resource (qualified) name.
+func (r *Report) name(resource Resource) string {
+	if resource.Namespace == "" {
+		return resource.Name
+	}
+
+	return resource.Namespace + "/" + resource.Name
+}
+
+//
+// Condition message with items expanded.
+func (r *Report) message(cnd condition.Condition) string {
+	cnd.ExpandItems()
+	return cnd.Message
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/jortel/controller/pkg/condition"
+	"github.com/onsi/gomega"
+	"strings"
+	"testing"
+)
+
+const manifest = `
+apiVersion: example.io/v1
+kind: Thing
+metadata:
+  namespace: ns0
+  name: joe
+status:
+  conditions:
+  - type: HostNotFound
+    status: "True"
+    category: Error
+    message: The hosts [A, B] not found.
+    lastTransitionTime: "2019-01-01T00:00:00Z"
+  - type: Ready
+    status: "False"
+    category: Required
+    lastTransitionTime: "2019-01-01T00:00:00Z"
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: example.io/v1
+  kind: Thing
+  metadata:
+    namespace: ns0
+    name: jeff
+  status:
+    conditions:
+    - type: HostNotFound
+      status: "True"
+      category: Error
+      message: The hosts [C] not found.
+      lastTransitionTime: "2019-01-01T00:00:00Z"
+`
+
+func TestReportRead(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	report := Report{}
+
+	// Test
+	err := report.Read("test.yaml", strings.NewReader(manifest))
+	report.Summarize()
+
+	// Validation
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(report.Resources)).To(gomega.Equal(2))
+	g.Expect(report.Resources[0].Name).To(gomega.Equal("joe"))
+	g.Expect(report.Resources[0].Conditions[0].Items).To(gomega.Equal([]string{"A", "B"}))
+	g.Expect(report.Resources[1].Name).To(gomega.Equal("jeff"))
+	g.Expect(report.Blockers).To(gomega.Equal([]Blocker{
+		{Type: "HostNotFound", Category: condition.Error, Count: 2},
+	}))
+}
+
+func TestReportCategory(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	report := Report{Categories: []string{"required"}}
+
+	// Test
+	err := report.Read("test.yaml", strings.NewReader(manifest))
+	report.Summarize()
+
+	// Validation
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(report.Resources[0].Conditions)).To(gomega.Equal(1))
+	g.Expect(report.Resources[0].Conditions[0].Type).To(gomega.Equal(condition.Ready))
+	g.Expect(len(report.Resources[1].Conditions)).To(gomega.Equal(0))
+	g.Expect(report.Blockers).To(gomega.Equal([]Blocker{
+		{Type: "HostNotFound", Category: condition.Error, Count: 2},
+	}))
+}
+
+func TestReportWrite(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	report := Report{}
+	err := report.Read("test.yaml", strings.NewReader(manifest))
+	g.Expect(err).To(gomega.BeNil())
+
+	// Test text.
+	out := &bytes.Buffer{}
+	err = report.Write(out, Text)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(out.String()).To(gomega.ContainSubstring("Thing ns0/joe (test.yaml)"))
+	g.Expect(out.String()).To(gomega.ContainSubstring("The hosts [A,B] not found."))
+
+	// Test markdown.
+	out = &bytes.Buffer{}
+	err = report.Write(out, Markdown)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(out.String()).To(gomega.ContainSubstring("| HostNotFound | Error | 2 |"))
+
+	// Test JSON.
+	out = &bytes.Buffer{}
+	err = report.Write(out, JSON)
+	g.Expect(err).To(gomega.BeNil())
+	decoded := Report{}
+	err = json.Unmarshal(out.Bytes(), &decoded)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(len(decoded.Resources)).To(gomega.Equal(2))
+	g.Expect(decoded.Resources[0].Conditions[0].Message).To(gomega.Equal("The hosts [A,B] not found."))
+	g.Expect(report.Resources[0].Conditions[0].Message).To(gomega.Equal("The hosts [] not found."))
+
+	// Test not supported.
+	err = report.Write(out, "xml")
+	g.Expect(err).NotTo(gomega.BeNil())
+}
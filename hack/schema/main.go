@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/jortel/controller/pkg/condition"
+	"os"
+	"sigs.k8s.io/yaml"
+	"strings"
+)
+
+//
+// Print the (CRD) validation schema fragment for conditions as YAML.
+// The fragment is indented (-indent) so it can be pasted into a CRD
+// under `status.properties`.
+//
+// Usage:
+//     go run hack/schema/main.go [-indent 0]
+//
+func main() {
+	indent := flag.Int("indent", 0, "The number of spaces to indent.")
+	flag.Parse()
+	fragment := map[string]condition.JSONSchemaProps{
+		"conditions": condition.ConditionsSchema(),
+	}
+	b, err := yaml.Marshal(fragment)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	prefix := strings.Repeat(" ", *indent)
+	for _, line := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		fmt.Println(prefix + line)
+	}
+}
@@ -11,8 +11,11 @@ import (
 
 // Types
 const (
-	ReconcileFailed = "ReconcileFailed"
-	Ready           = "Ready"
+	ReconcileFailed    = "ReconcileFailed"
+	Ready              = "Ready"
+	DependencyNotReady = "DependencyNotReady"
+	InvalidRef         = "InvalidRef"
+	DeletionBlocked    = "DeletionBlocked"
 )
 
 // Status
@@ -35,6 +38,12 @@ const (
 	Advisory = "Advisory"
 )
 
+// Known statuses.
+var Statuses = []string{True, False}
+
+// Known categories.
+var Categories = []string{Critical, Error, Warn, Required, Advisory}
+
 // Condition
 type Condition struct {
 	// The condition type.
@@ -49,8 +58,15 @@ type Condition struct {
 	Message string `json:"message,omitempty"`
 	// When the last status transition occurred.
 	LastTransitionTime v1.Time `json:"lastTransitionTime"`
+	// When the condition was last probed (staged).
+	// See: Conditions.SetProbeInterval().
+	LastProbeTime *v1.Time `json:"lastProbeTime,omitempty"`
 	// The condition is durable - never un-staged.
 	Durable bool `json:"durable,omitempty"`
+	// The owner (writer) of the condition.
+	// Used to scope staging when multiple controllers
+	// write conditions to the same resource.
+	Owner string `json:"owner,omitempty"`
 	// A list of associated `items` used to replace [] in `Message`.
 	Items []string `json:"-"`
 	// A condition has been explicitly set/updated.
@@ -59,8 +75,12 @@ type Condition struct {
 
 //
 // Update this condition with another's fields.
+// A change of `Owner` is not a transition.
 func (r *Condition) Update(other Condition) {
 	r.staged = true
+	if other.Owner != "" {
+		r.Owner = other.Owner
+	}
 	if r.Equal(other) {
 		return
 	}
@@ -76,6 +96,7 @@ func (r *Condition) Update(other Condition) {
 
 //
 // Get whether the conditions are equal.
+// The `Owner` is not compared.
 func (r *Condition) Equal(other Condition) bool {
 	return r.Type == other.Type &&
 		r.Status == other.Status &&
@@ -116,6 +137,13 @@ func (r *Condition) BuildItems() {
 // List - The list of conditions.
 // staging - In `staging` mode, the search methods like
 //          HasCondition() filter out un-staging conditions.
+// owner - The (optional) owner used to scope staging. Only
+//         conditions with a matching `Owner` are un-staged.
+//         Conditions without an `Owner` (written before scoping)
+//         are claimed by the owner that sets, stages or deletes them.
+// order - The (optional) ordering. See: SetOrder().
+// probeInterval - The (optional) probe interval.
+//         See: SetProbeInterval().
 // -------------------
 // Example:
 //
@@ -128,43 +156,99 @@ func (r *Condition) BuildItems() {
 //     !thing.Status.HasBlockerCondition(),
 //     "Resource Ready.")
 //
+// Example (multiple writers):
+//
+// thing.Status.BeginStagingConditions("controller-A")
+// thing.Status.SetCondition(c)
+// thing.Status.EndStagingConditions("controller-A")
+//
 type Conditions struct {
-	List    []Condition `json:"conditions"`
-	staging bool
+	List          []Condition `json:"conditions"`
+	staging       bool
+	owner         string
+	savepoints    [][]Condition
+	migrated      []Migration
+	order         Order
+	probeInterval time.Duration
 }
 
 //
 // Begin staging conditions.
-func (r *Conditions) BeginStagingConditions() {
+// When an `owner` is specified, only conditions with
+// a matching `Owner` are un-staged.
+// Registered `Migrations` are applied.
+func (r *Conditions) BeginStagingConditions(owner ...string) {
 	r.staging = true
+	r.owner = r.ownerOf(owner)
+	r.migrated = Migrations.Migrate(r)
 	if r.List == nil {
 		return
 	}
 	for index := range r.List {
 		condition := &r.List[index]
 		condition.BuildItems()
-		condition.staged = condition.Durable
+		condition.staged = condition.Durable || !r.owned(condition)
 	}
 }
 
 //
 // End staging conditions. Un-staged conditions are deleted.
-func (r *Conditions) EndStagingConditions() {
-	r.staging = false
+// When an `owner` is specified, only un-staged conditions with
+// a matching `Owner` are deleted. Defaults to the owner
+// specified in BeginStagingConditions().
+// The list is ordered. See: SetOrder().
+func (r *Conditions) EndStagingConditions(owner ...string) {
+	if len(owner) > 0 {
+		r.owner = r.ownerOf(owner)
+	}
+	defer func() {
+		r.staging = false
+		r.owner = ""
+		r.savepoints = nil
+	}()
 	if r.List == nil {
 		return
 	}
 	kept := []Condition{}
 	for index := range r.List {
 		condition := r.List[index]
-		if condition.staged {
+		if condition.staged || !r.owned(&condition) {
 			condition.ExpandItems()
 			kept = append(kept, condition)
 		}
 	}
+	r.ordering()(kept)
 	r.List = kept
 }
 
+//
+// Get the (optional) owner.
+func (r *Conditions) ownerOf(owner []string) string {
+	if len(owner) > 0 {
+		return owner[0]
+	}
+
+	return ""
+}
+
+//
+// Get whether the condition is owned by the staging owner.
+// All conditions are owned when staging is not scoped.
+func (r *Conditions) owned(condition *Condition) bool {
+	return r.owner == "" || condition.Owner == r.owner
+}
+
+//
+// Claim an unowned condition for the staging owner.
+// Returns whether the condition is owned by the staging owner.
+func (r *Conditions) claim(condition *Condition) bool {
+	if r.owner != "" && condition.Owner == "" {
+		condition.Owner = r.owner
+	}
+
+	return r.owned(condition)
+}
+
 //
 // Find a condition by type.
 // Staging is ignored.
@@ -206,12 +290,18 @@ func (r *Conditions) SetCondition(condition Condition) {
 		r.List = []Condition{}
 	}
 	condition.staged = true
+	if condition.Owner == "" {
+		condition.Owner = r.owner
+	}
 	found := r.find(condition.Type)
 	if found == nil {
 		condition.LastTransitionTime = v1.NewTime(time.Now())
+		condition.LastProbeTime = nil
+		condition.probe(r.probeInterval)
 		r.List = append(r.List, condition)
 	} else {
 		found.Update(condition)
+		found.probe(r.probeInterval)
 	}
 }
 
@@ -228,13 +318,17 @@ func (r *Conditions) StageCondition(types ...string) {
 	for i := range r.List {
 		condition := &r.List[i]
 		if _, found := filter[condition.Type]; found {
+			r.claim(condition)
 			condition.staged = true
+			condition.probe(r.probeInterval)
 		}
 	}
 }
 
 //
 // Delete conditions by type.
+// When staging is scoped by owner, conditions of other
+// owners are not affected.
 func (r *Conditions) DeleteCondition(types ...string) {
 	if r.List == nil {
 		return
@@ -252,7 +346,9 @@ func (r *Conditions) DeleteCondition(types ...string) {
 			continue
 		}
 		if r.staging {
-			condition.staged = false
+			if r.claim(&condition) {
+				condition.staged = false
+			}
 			kept = append(kept, condition)
 		}
 	}
@@ -448,3 +448,427 @@ func TestConditions_AppendingItems(t *testing.T) {
 	// Validation
 	g.Expect(conditions.List[0].Message).To(gomega.Equal("These things [Dog,Cat] not found."))
 }
+
+func TestConditions_StagingOwner(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	conditions := Conditions{
+		List: []Condition{
+			{Type: "A", Owner: "ctl-A"},
+			{Type: "B", Owner: "ctl-A"},
+			{Type: "C", Owner: "ctl-B"},
+			{Type: "D"},
+		},
+	}
+
+	// Test
+	conditions.BeginStagingConditions("ctl-A")
+	conditions.SetCondition(Condition{Type: "A"})
+	conditions.SetCondition(Condition{Type: "E"})
+
+	// Validation
+	g.Expect(conditions.FindCondition("A")).NotTo(gomega.BeNil())
+	g.Expect(conditions.FindCondition("B")).To(gomega.BeNil())
+	g.Expect(conditions.FindCondition("C")).NotTo(gomega.BeNil())
+	g.Expect(conditions.FindCondition("D")).NotTo(gomega.BeNil())
+
+	// Test
+	conditions.EndStagingConditions("ctl-A")
+
+	// Validation
+	types := []string{}
+	for _, condition := range conditions.List {
+		types = append(types, condition.Type)
+	}
+	g.Expect(conditions.staging).To(gomega.BeFalse())
+	g.Expect(types).To(gomega.Equal([]string{"A", "C", "D", "E"}))
+	g.Expect(conditions.FindCondition("E").Owner).To(gomega.Equal("ctl-A"))
+}
+
+func TestConditions_StagingOwnerEnd(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	conditions := Conditions{
+		List: []Condition{
+			{Type: "A", Owner: "ctl-A"},
+			{Type: "B", Owner: "ctl-B"},
+		},
+	}
+
+	// Test
+	conditions.BeginStagingConditions("ctl-A")
+	conditions.DeleteCondition("B")
+
+	// Validation
+	g.Expect(conditions.FindCondition("B")).NotTo(gomega.BeNil())
+
+	// Test
+	conditions.EndStagingConditions()
+
+	// Validation
+	g.Expect(len(conditions.List)).To(gomega.Equal(1))
+	g.Expect(conditions.List[0].Type).To(gomega.Equal("B"))
+}
+
+func TestConditions_StagingOwnerClaim(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	conditions := Conditions{
+		List: []Condition{
+			{Type: "A"},
+			{Type: "B"},
+			{Type: "C"},
+		},
+	}
+
+	// Test
+	conditions.BeginStagingConditions("ctl-A")
+	conditions.StageCondition("A")
+	conditions.DeleteCondition("B")
+	conditions.EndStagingConditions()
+
+	// Validation
+	g.Expect(len(conditions.List)).To(gomega.Equal(2))
+	g.Expect(conditions.FindCondition("A").Owner).To(gomega.Equal("ctl-A"))
+	g.Expect(conditions.FindCondition("B")).To(gomega.BeNil())
+	g.Expect(conditions.FindCondition("C").Owner).To(gomega.Equal(""))
+
+	// Test
+	conditions.BeginStagingConditions("ctl-A")
+	conditions.EndStagingConditions()
+
+	// Validation
+	g.Expect(len(conditions.List)).To(gomega.Equal(1))
+	g.Expect(conditions.List[0].Type).To(gomega.Equal("C"))
+}
+
+func TestConditionsSchema(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Test
+	schema := ConditionsSchema()
+
+	// Validation
+	g.Expect(schema.Type).To(gomega.Equal(tArray))
+	g.Expect(schema.Items.Properties["status"].Enum).To(gomega.Equal(Statuses))
+	g.Expect(schema.Items.Properties["category"].Enum).To(gomega.Equal(Categories))
+	g.Expect(schema.Items.Properties).NotTo(gomega.HaveKey("Items"))
+}
+
+func TestConditions_EndStagingOrdering(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	list := []Condition{
+		{Type: "A", Category: Advisory, staged: true},
+		{Type: "B", Category: Warn, staged: true},
+		{Type: "D", Category: Critical, staged: true},
+		{Type: "C", Category: Critical, staged: true},
+		{Type: "E", Category: Required, staged: true},
+		{Type: "F", Category: Error, staged: true},
+	}
+	types := func(list []Condition) []string {
+		types := []string{}
+		for _, condition := range list {
+			types = append(types, condition.Type)
+		}
+		return types
+	}
+
+	// Test by severity.
+	conditions := Conditions{List: append([]Condition{}, list...)}
+	conditions.EndStagingConditions()
+
+	// Validation
+	g.Expect(types(conditions.List)).To(gomega.Equal([]string{"C", "D", "F", "B", "E", "A"}))
+
+	// Test insertion order.
+	conditions = Conditions{List: append([]Condition{}, list...)}
+	conditions.SetOrder(InsertionOrder)
+	conditions.EndStagingConditions()
+
+	// Validation
+	g.Expect(types(conditions.List)).To(gomega.Equal([]string{"A", "B", "D", "C", "E", "F"}))
+}
+
+func TestConditions_Worst(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	conditions := Conditions{
+		List: []Condition{
+			{Type: "A", Category: Advisory, Status: True},
+			{Type: "B", Category: Critical, Status: False},
+			{Type: "D", Category: Error, Status: True},
+			{Type: "C", Category: Error, Status: True},
+		},
+	}
+
+	// Test
+	worst := conditions.Worst()
+
+	// Validation
+	g.Expect(worst.Type).To(gomega.Equal("C"))
+	g.Expect((&Conditions{}).Worst()).To(gomega.BeNil())
+}
+
+func TestConditions_Savepoint(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	conditions := Conditions{
+		List: []Condition{
+			{Type: "A"},
+			{Type: "B"},
+			{Type: "C"},
+		},
+	}
+	staged := func() []string {
+		list := []string{}
+		for _, condition := range conditions.List {
+			if condition.staged {
+				list = append(list, condition.Type)
+			}
+		}
+		return list
+	}
+
+	// Test
+	conditions.BeginStagingConditions()
+	conditions.SetCondition(Condition{Type: "A"})
+	sp0 := conditions.Savepoint()
+	conditions.SetCondition(Condition{Type: "B"})
+	conditions.DeleteCondition("A")
+	conditions.SetCondition(Condition{Type: "D"})
+	g.Expect(staged()).To(gomega.Equal([]string{"B", "D"}))
+	sp1 := conditions.Savepoint()
+	conditions.DeleteCondition("B")
+	conditions.SetCondition(Condition{Type: "E"})
+	g.Expect(staged()).To(gomega.Equal([]string{"D", "E"}))
+
+	// Test rollback (nested).
+	conditions.Rollback(sp1)
+	g.Expect(staged()).To(gomega.Equal([]string{"B", "D"}))
+	g.Expect(conditions.FindCondition("E")).To(gomega.BeNil())
+
+	// Test rollback (outer).
+	conditions.Rollback(sp0)
+	g.Expect(staged()).To(gomega.Equal([]string{"A"}))
+	g.Expect(conditions.FindCondition("D")).To(gomega.BeNil())
+
+	// Test rollback (already rolled back).
+	conditions.SetCondition(Condition{Type: "C"})
+	conditions.Rollback(sp1)
+	g.Expect(staged()).To(gomega.Equal([]string{"A", "C"}))
+
+	// Validation
+	conditions.EndStagingConditions()
+	g.Expect(len(conditions.List)).To(gomega.Equal(2))
+}
+
+func TestConditions_SavepointRelease(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	conditions := Conditions{
+		List: []Condition{
+			{Type: "A"},
+			{Type: "B"},
+		},
+	}
+
+	// Test
+	conditions.BeginStagingConditions()
+	sp0 := conditions.Savepoint()
+	conditions.SetCondition(Condition{Type: "A"})
+	sp1 := conditions.Savepoint()
+	conditions.DeleteCondition("A")
+	conditions.SetCondition(Condition{Type: "C"})
+	conditions.Release(sp1)
+	g.Expect(conditions.FindCondition("A")).To(gomega.BeNil())
+	g.Expect(conditions.FindCondition("C")).NotTo(gomega.BeNil())
+	conditions.Rollback(sp0)
+	g.Expect(conditions.FindCondition("C")).To(gomega.BeNil())
+	g.Expect(len(conditions.List)).To(gomega.Equal(2))
+	g.Expect(len(conditions.savepoints)).To(gomega.Equal(0))
+}
+
+func TestConditions_Probe(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	conditions := Conditions{}
+
+	// Test disabled.
+	conditions.SetCondition(Condition{Type: "A"})
+	g.Expect(conditions.FindCondition("A").LastProbeTime).To(gomega.BeNil())
+	g.Expect(conditions.FindStaleConditions(time.Minute * 5)).To(gomega.BeEmpty())
+	conditions = Conditions{}
+	conditions.SetProbeInterval(time.Minute)
+
+	// Test set.
+	conditions.SetCondition(Condition{Type: "A"})
+	condition := conditions.FindCondition("A")
+	g.Expect(condition.LastProbeTime).NotTo(gomega.BeNil())
+	transitioned := condition.LastTransitionTime
+
+	// Test coarse (not refreshed).
+	probed := metav1.NewTime(time.Now().Add(-time.Second * 30))
+	condition.LastProbeTime = &probed
+	conditions.SetCondition(Condition{Type: "A"})
+	g.Expect(condition.LastProbeTime).To(gomega.Equal(&probed))
+
+	// Test refreshed.
+	probed = metav1.NewTime(time.Now().Add(-time.Minute * 2))
+	condition.LastProbeTime = &probed
+	conditions.StageCondition("A")
+	g.Expect(condition.LastProbeTime.After(probed.Time)).To(gomega.BeTrue())
+	g.Expect(condition.LastTransitionTime).To(gomega.Equal(transitioned))
+	g.Expect(conditions.FindStaleConditions(time.Minute * 5)).To(gomega.BeEmpty())
+
+	// Test stale.
+	probed = metav1.NewTime(time.Now().Add(-time.Minute * 10))
+	condition.LastProbeTime = &probed
+	stale := conditions.FindStaleConditions(time.Minute * 5)
+	g.Expect(len(stale)).To(gomega.Equal(1))
+	g.Expect(stale[0].Type).To(gomega.Equal("A"))
+}
+
+func TestConditions_Migration(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	Migrations.RenameType("OldA", "A")
+	Migrations.RenameType("OldB", "B")
+	Migrations.RenameCategory("Warning", Warn)
+	defer Migrations.Reset()
+	transitioned := metav1.NewTime(time.Now().Add(-time.Hour))
+	conditions := Conditions{
+		List: []Condition{
+			{Type: "OldA", Category: "Warning", LastTransitionTime: transitioned},
+			{Type: "OldB", Category: Error},
+			{Type: "B", Category: Error},
+			{Type: "C", Category: Error},
+		},
+	}
+
+	// Test
+	conditions.BeginStagingConditions()
+	conditions.SetCondition(Condition{Type: "A", Category: Warn})
+	conditions.StageCondition("B")
+
+	// Validation
+	g.Expect(conditions.Migrated()).To(gomega.Equal([]Migration{
+		{Condition: "A", Field: TypeField, From: "OldA", To: "A"},
+		{Condition: "A", Field: CategoryField, From: "Warning", To: Warn},
+		{Condition: "B", Field: TypeField, From: "OldB", To: "B"},
+	}))
+	condition := conditions.FindCondition("A")
+	g.Expect(condition).NotTo(gomega.BeNil())
+	g.Expect(condition.LastTransitionTime).To(gomega.Equal(transitioned))
+	conditions.EndStagingConditions()
+	g.Expect(len(conditions.List)).To(gomega.Equal(2))
+}
+
+func TestConditions_Hash(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	a := Conditions{
+		List: []Condition{
+			{Type: "A", Status: True, Message: "Things [X,Y] not found."},
+			{Type: "B", Status: True, LastTransitionTime: metav1.NewTime(time.Now())},
+		},
+	}
+	b := Conditions{
+		List: []Condition{
+			{Type: "B", Status: True},
+			{Type: "A", Status: True, Message: "Things [] not found.", Items: []string{"X", "Y"}},
+		},
+	}
+
+	// Test equal.
+	g.Expect(a.Hash()).To(gomega.Equal(b.Hash()))
+	g.Expect(SemanticEqual(&a, &b)).To(gomega.BeTrue())
+	g.Expect(len(a.Hash())).To(gomega.Equal(64))
+
+	// Test staging.
+	b.BeginStagingConditions()
+	b.StageCondition("A", "B")
+	g.Expect(SemanticEqual(&a, &b)).To(gomega.BeTrue())
+	b.DeleteCondition("B")
+	g.Expect(SemanticEqual(&a, &b)).To(gomega.BeFalse())
+
+	// Test not equal.
+	b.EndStagingConditions()
+	b.SetCondition(Condition{Type: "B", Status: False})
+	g.Expect(SemanticEqual(&a, &b)).To(gomega.BeFalse())
+}
+
+func TestTemplate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	hostUnreachable := Template{
+		Type:     "HostUnreachable",
+		Reason:   "NotReachable",
+		Category: Critical,
+		Message:  "The host {host} is not reachable on ports: [].",
+	}
+	invalidCredentials := Template{
+		Type:     "InvalidCredentials",
+		Category: Critical,
+		Durable:  true,
+		Message:  "The credentials are not valid.",
+	}
+	set := NewTemplateSet(hostUnreachable).Merge(NewTemplateSet(invalidCredentials))
+	conditions := Conditions{}
+
+	// Test
+	tmpl, found := set.Get("HostUnreachable")
+	g.Expect(found).To(gomega.BeTrue())
+	conditions.BeginStagingConditions()
+	conditions.SetCondition(tmpl.With(Params{"host": "h1"}).New("22", "443"))
+	conditions.SetCondition(invalidCredentials.New())
+	conditions.EndStagingConditions()
+
+	// Validation
+	g.Expect(set.Types()).To(gomega.Equal([]string{"HostUnreachable", "InvalidCredentials"}))
+	condition := conditions.FindCondition("HostUnreachable")
+	g.Expect(condition.Status).To(gomega.Equal(True))
+	g.Expect(condition.Reason).To(gomega.Equal("NotReachable"))
+	g.Expect(condition.Message).To(gomega.Equal("The host h1 is not reachable on ports: [22,443]."))
+	g.Expect(hostUnreachable.Message).To(gomega.Equal("The host {host} is not reachable on ports: []."))
+	g.Expect(conditions.FindCondition("InvalidCredentials").Durable).To(gomega.BeTrue())
+}
+
+func TestConditions_OwnerNotTransition(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	ltt := metav1.NewTime(time.Now().Add(-time.Hour))
+	conditions := Conditions{
+		List: []Condition{
+			{
+				Type:               Ready,
+				Status:             True,
+				Category:           Required,
+				LastTransitionTime: ltt,
+			},
+		},
+	}
+
+	// Test
+	conditions.BeginStagingConditions("ctl-A")
+	conditions.SetReady(true, "")
+	conditions.EndStagingConditions()
+
+	// Validation
+	ready := conditions.FindCondition(Ready)
+	g.Expect(ready).NotTo(gomega.BeNil())
+	g.Expect(ready.Owner).To(gomega.Equal("ctl-A"))
+	g.Expect(ready.LastTransitionTime).To(gomega.Equal(ltt))
+}
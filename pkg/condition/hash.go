@@ -0,0 +1,70 @@
+package condition
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+//
+// Canonical (semantic) representation of a condition.
+// Timestamps, staging and ownership are excluded.
+type canonical struct {
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+	Reason   string `json:"reason"`
+	Category string `json:"category"`
+	Message  string `json:"message"`
+	Durable  bool   `json:"durable"`
+}
+
+//
+// Get a deterministic (SHA-256) hash of the semantic content.
+// Canonicalization:
+//   - Un-staged conditions are excluded while staging.
+//   - Ordered by type.
+//   - `Items` expanded in the `Message`.
+//   - Timestamps and the `Owner` are excluded.
+// Intended for change detection. Eg: stored in an annotation.
+func (r *Conditions) Hash() string {
+	b, _ := json.Marshal(r.canonical())
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+//
+// Get whether the semantic content of the conditions is equal.
+// See: Hash().
+func SemanticEqual(a, b *Conditions) bool {
+	return a.Hash() == b.Hash()
+}
+
+//
+// Build the canonical representation.
+func (r *Conditions) canonical() []canonical {
+	list := []canonical{}
+	for _, condition := range r.List {
+		if r.staging && !condition.staged {
+			continue
+		}
+		if condition.Items != nil {
+			condition.ExpandItems()
+		}
+		list = append(
+			list,
+			canonical{
+				Type:     condition.Type,
+				Status:   condition.Status,
+				Reason:   condition.Reason,
+				Category: condition.Category,
+				Message:  condition.Message,
+				Durable:  condition.Durable,
+			})
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].Type < list[j].Type
+	})
+
+	return list
+}
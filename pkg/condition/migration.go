@@ -0,0 +1,142 @@
+package condition
+
+import (
+	"sync"
+)
+
+// Migrated fields.
+const (
+	TypeField     = "Type"
+	CategoryField = "Category"
+)
+
+// Global
+var Migrations *MigrationRegistry
+
+//
+// Build globals.
+func init() {
+	Migrations = &MigrationRegistry{}
+}
+
+//
+// A (fired) migration.
+type Migration struct {
+	// The condition type (after migration).
+	Condition string
+	// The migrated field.
+	Field string
+	// The legacy value.
+	From string
+	// The current value.
+	To string
+}
+
+//
+// Registry of condition schema migrations.
+// Conditions persisted with legacy types and categories
+// are migrated by BeginStagingConditions().
+// The `LastTransitionTime` is preserved.
+//
+// Example:
+//     condition.Migrations.RenameType("HostNotFound", "HostUnreachable")
+//     condition.Migrations.RenameCategory("Warning", condition.Warn)
+//     ...
+//     thing.Status.BeginStagingConditions()
+//     for _, m := range thing.Status.Migrated() {
+//         log.Info("migrated", "condition", m.Condition, "from", m.From)
+//     }
+//
+// +k8s:deepcopy-gen=false
+type MigrationRegistry struct {
+	types      map[string]string
+	categories map[string]string
+	mutex      sync.RWMutex
+}
+
+//
+// Register a type migration.
+func (r *MigrationRegistry) RenameType(old, new string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.types == nil {
+		r.types = map[string]string{}
+	}
+	r.types[old] = new
+}
+
+//
+// Register a category migration.
+func (r *MigrationRegistry) RenameCategory(old, new string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.categories == nil {
+		r.categories = map[string]string{}
+	}
+	r.categories[old] = new
+}
+
+//
+// Reset (clear) the registry.
+func (r *MigrationRegistry) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.types = nil
+	r.categories = nil
+}
+
+//
+// Migrate the conditions.
+// A legacy condition is deleted when a condition with
+// the new type already exists.
+// Returns the list of migrations that fired.
+func (r *MigrationRegistry) Migrate(conditions *Conditions) []Migration {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	fired := []Migration{}
+	if conditions.List == nil {
+		return fired
+	}
+	present := map[string]bool{}
+	for _, condition := range conditions.List {
+		present[condition.Type] = true
+	}
+	kept := []Condition{}
+	for _, condition := range conditions.List {
+		if new, found := r.types[condition.Type]; found {
+			fired = append(
+				fired,
+				Migration{
+					Condition: new,
+					Field:     TypeField,
+					From:      condition.Type,
+					To:        new,
+				})
+			if present[new] {
+				continue
+			}
+			present[new] = true
+			condition.Type = new
+		}
+		if new, found := r.categories[condition.Category]; found {
+			fired = append(
+				fired,
+				Migration{
+					Condition: condition.Type,
+					Field:     CategoryField,
+					From:      condition.Category,
+					To:        new,
+				})
+			condition.Category = new
+		}
+		kept = append(kept, condition)
+	}
+	conditions.List = kept
+	return fired
+}
+
+//
+// Get the migrations fired by BeginStagingConditions().
+func (r *Conditions) Migrated() []Migration {
+	return r.migrated
+}
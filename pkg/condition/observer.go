@@ -0,0 +1,74 @@
+package condition
+
+import (
+	"sync"
+)
+
+//
+// Protect the observers.
+var observerMutex sync.RWMutex
+
+//
+// Registered observers.
+var observers []Observer
+
+//
+// Key used to identify the resource that contains the conditions.
+type Key struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+//
+// Observes the conditions of resources.
+type Observer interface {
+	// The staged conditions of a resource.
+	Observe(key Key, conditions *Conditions)
+	// The resource has been deleted.
+	Forget(key Key)
+}
+
+//
+// Register an observer.
+func AddObserver(observer Observer) {
+	observerMutex.Lock()
+	defer observerMutex.Unlock()
+	observers = append(observers, observer)
+}
+
+//
+// Unregister an observer.
+func RemoveObserver(observer Observer) {
+	observerMutex.Lock()
+	defer observerMutex.Unlock()
+	kept := []Observer{}
+	for _, o := range observers {
+		if o != observer {
+			kept = append(kept, o)
+		}
+	}
+	observers = kept
+}
+
+//
+// Notify observers that a resource has been deleted.
+func Forget(key Key) {
+	observerMutex.RLock()
+	defer observerMutex.RUnlock()
+	for _, o := range observers {
+		o.Forget(key)
+	}
+}
+
+//
+// End staging conditions and notify observers.
+// See: EndStagingConditions().
+func (r *Conditions) EndStagingConditionsFor(key Key, owner ...string) {
+	r.EndStagingConditions(owner...)
+	observerMutex.RLock()
+	defer observerMutex.RUnlock()
+	for _, o := range observers {
+		o.Observe(key, r)
+	}
+}
@@ -0,0 +1,65 @@
+package condition
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+//
+// Set the probe (heartbeat) interval.
+// When > 0, the `LastProbeTime` is refreshed when a condition
+// is staged and the last probe is older than the interval.
+// The coarse refresh prevents a status update on every
+// reconcile. Disabled (0) by default.
+func (r *Conditions) SetProbeInterval(interval time.Duration) {
+	r.probeInterval = interval
+}
+
+//
+// Refresh the `LastProbeTime`.
+// Does not count as a transition.
+func (r *Condition) probe(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	now := time.Now()
+	if r.LastProbeTime != nil && now.Sub(r.LastProbeTime.Time) < interval {
+		return
+	}
+	probed := v1.NewTime(now.Truncate(time.Second))
+	r.LastProbeTime = &probed
+}
+
+//
+// Get whether the condition has not been probed within the window.
+// Conditions never probed are stale.
+func (r *Condition) Stale(window time.Duration) bool {
+	if r.LastProbeTime == nil {
+		return true
+	}
+
+	return time.Since(r.LastProbeTime.Time) > window
+}
+
+//
+// Find conditions not probed within the window.
+// The window should be larger than the probe interval.
+// Nothing is found when probing is disabled.
+// See: SetProbeInterval().
+func (r *Conditions) FindStaleConditions(window time.Duration) []*Condition {
+	list := []*Condition{}
+	if r.probeInterval <= 0 {
+		return list
+	}
+	for i := range r.List {
+		condition := &r.List[i]
+		if r.staging && !condition.staged {
+			continue
+		}
+		if condition.Stale(window) {
+			list = append(list, condition)
+		}
+	}
+
+	return list
+}
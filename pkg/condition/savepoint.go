@@ -0,0 +1,67 @@
+package condition
+
+//
+// A staging savepoint.
+// Returned by Savepoint() and used to Rollback() or Release().
+type Savepoint int
+
+//
+// Create a savepoint.
+// Savepoints are nested. Rolling back (or releasing) a savepoint
+// also discards all of the savepoints created after it.
+// Conditions previously returned by FindCondition() must
+// not be used after a Rollback().
+//
+// Example:
+//
+// thing.Status.BeginStagingConditions()
+// thing.Status.SetCondition(c)
+// sp := thing.Status.Savepoint()
+// err := step()
+// if err != nil {
+//     thing.Status.Rollback(sp)
+// } else {
+//     thing.Status.Release(sp)
+// }
+// thing.Status.EndStagingConditions()
+//
+func (r *Conditions) Savepoint() Savepoint {
+	list := []Condition{}
+	for i := range r.List {
+		condition := Condition{}
+		r.List[i].DeepCopyInto(&condition)
+		list = append(list, condition)
+	}
+	if r.List == nil {
+		list = nil
+	}
+	r.savepoints = append(r.savepoints, list)
+	return Savepoint(len(r.savepoints) - 1)
+}
+
+//
+// Rollback to the savepoint.
+// Both the list of conditions and the staged flags are restored.
+func (r *Conditions) Rollback(sp Savepoint) {
+	if !r.valid(sp) {
+		return
+	}
+	r.List = r.savepoints[sp]
+	r.savepoints = r.savepoints[:sp]
+}
+
+//
+// Release the savepoint.
+// The changes made since the savepoint are kept.
+func (r *Conditions) Release(sp Savepoint) {
+	if !r.valid(sp) {
+		return
+	}
+	r.savepoints = r.savepoints[:sp]
+}
+
+//
+// Get whether the savepoint is valid.
+func (r *Conditions) valid(sp Savepoint) bool {
+	return sp >= 0 && int(sp) < len(r.savepoints)
+}
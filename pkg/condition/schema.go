@@ -0,0 +1,98 @@
+package condition
+
+// Schema types.
+const (
+	tString  = "string"
+	tBoolean = "boolean"
+	tArray   = "array"
+	tObject  = "object"
+)
+
+//
+// OpenAPI v3 schema.
+// A subset of the apiextensions `JSONSchemaProps` with the same
+// JSON encoding so the fragment may be composed into a CRD
+// validation schema.
+// +k8s:deepcopy-gen=false
+type JSONSchemaProps struct {
+	Type        string                     `json:"type,omitempty"`
+	Format      string                     `json:"format,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Enum        []string                   `json:"enum,omitempty"`
+	Required    []string                   `json:"required,omitempty"`
+	Items       *JSONSchemaProps           `json:"items,omitempty"`
+	Properties  map[string]JSONSchemaProps `json:"properties,omitempty"`
+}
+
+//
+// Get the schema for a `Condition`.
+func ConditionSchema() JSONSchemaProps {
+	return JSONSchemaProps{
+		Type: tObject,
+		Required: []string{
+			"type",
+			"status",
+			"category",
+			"lastTransitionTime",
+		},
+		Properties: map[string]JSONSchemaProps{
+			"type": {
+				Type:        tString,
+				Description: "The condition type.",
+			},
+			"status": {
+				Type:        tString,
+				Description: "The condition status.",
+				Enum:        Statuses,
+			},
+			"reason": {
+				Type:        tString,
+				Description: "The reason for the condition or transition.",
+			},
+			"category": {
+				Type:        tString,
+				Description: "The condition category.",
+				Enum:        Categories,
+			},
+			"message": {
+				Type:        tString,
+				Description: "The human readable description of the condition.",
+			},
+			"lastTransitionTime": {
+				Type:        tString,
+				Format:      "date-time",
+				Description: "When the last status transition occurred.",
+			},
+			"lastProbeTime": {
+				Type:        tString,
+				Format:      "date-time",
+				Description: "When the condition was last probed (staged).",
+			},
+			"durable": {
+				Type:        tBoolean,
+				Description: "The condition is durable - never un-staged.",
+			},
+			"owner": {
+				Type:        tString,
+				Description: "The owner (writer) of the condition.",
+			},
+		},
+	}
+}
+
+//
+// Get the schema for `Conditions`.
+// The fragment is the `conditions` property intended to be
+// added to the properties of a resource Status.
+//
+// Example:
+//     status.Properties["conditions"] = condition.ConditionsSchema()
+//
+func ConditionsSchema() JSONSchemaProps {
+	item := ConditionSchema()
+	return JSONSchemaProps{
+		Type:        tArray,
+		Description: "The list of conditions.",
+		Items:       &item,
+	}
+}
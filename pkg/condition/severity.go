@@ -0,0 +1,90 @@
+package condition
+
+import (
+	"sort"
+)
+
+// Severity (by category).
+// Critical > Error > Warn > Required > Advisory.
+var severity = map[string]int{
+	Critical: 5,
+	Error:    4,
+	Warn:     3,
+	Required: 2,
+	Advisory: 1,
+}
+
+//
+// Order conditions.
+type Order func(list []Condition)
+
+//
+// Set the ordering applied by EndStagingConditions().
+// Use `InsertionOrder` to keep the insertion order.
+// Default: BySeverity.
+func (r *Conditions) SetOrder(order Order) {
+	r.order = order
+}
+
+//
+// The ordering applied by EndStagingConditions().
+func (r *Conditions) ordering() Order {
+	if r.order != nil {
+		return r.order
+	}
+
+	return BySeverity
+}
+
+//
+// Order by severity (descending) then type.
+func BySeverity(list []Condition) {
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := &list[i], &list[j]
+		if a.Severity() != b.Severity() {
+			return a.Severity() > b.Severity()
+		}
+		return a.Type < b.Type
+	})
+}
+
+//
+// Keep the insertion order.
+func InsertionOrder(list []Condition) {
+}
+
+//
+// Get the severity of a category.
+// Unknown categories have a severity of 0.
+func Severity(category string) int {
+	return severity[category]
+}
+
+//
+// Get the severity of the condition.
+func (r *Condition) Severity() int {
+	return Severity(r.Category)
+}
+
+//
+// Get the (`True`) condition with the highest severity.
+// Ties are resolved by type.
+func (r *Conditions) Worst() *Condition {
+	var worst *Condition
+	for i := range r.List {
+		condition := &r.List[i]
+		if condition.Status != True {
+			continue
+		}
+		if r.staging && !condition.staged {
+			continue
+		}
+		if worst == nil ||
+			condition.Severity() > worst.Severity() ||
+			(condition.Severity() == worst.Severity() && condition.Type < worst.Type) {
+			worst = condition
+		}
+	}
+
+	return worst
+}
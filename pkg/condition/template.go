@@ -0,0 +1,139 @@
+package condition
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+//
+// Template parameters.
+// Replace `{name}` in the template `Message`.
+type Params map[string]string
+
+//
+// A named condition template.
+// Declared once and instantiated with parameters and `Items`
+// for consistent conditions across controllers.
+//
+// Example:
+//     var HostUnreachable = condition.Template{
+//         Type:     "HostUnreachable",
+//         Reason:   "NotReachable",
+//         Category: condition.Critical,
+//         Message:  "The host {host} is not reachable on ports: [].",
+//     }
+//     ...
+//     thing.Status.SetCondition(
+//         HostUnreachable.With(condition.Params{"host": host}).New("22", "443"))
+//
+// +k8s:deepcopy-gen=false
+type Template struct {
+	// The condition type.
+	Type string
+	// The condition status. Default: True.
+	Status string
+	// The reason for the condition.
+	Reason string
+	// The condition category.
+	Category string
+	// The message. May contain [] and {param}.
+	Message string
+	// The condition is durable.
+	Durable bool
+}
+
+//
+// Get a copy of the template with the parameters
+// replaced in the `Message`.
+func (t Template) With(params Params) Template {
+	pairs := []string{}
+	for name, value := range params {
+		pairs = append(pairs, fmt.Sprintf("{%s}", name), value)
+	}
+	t.Message = strings.NewReplacer(pairs...).Replace(t.Message)
+	return t
+}
+
+//
+// Instantiate a condition with the (optional) items.
+func (t Template) New(items ...string) Condition {
+	status := t.Status
+	if status == "" {
+		status = True
+	}
+	condition := Condition{
+		Type:     t.Type,
+		Status:   status,
+		Reason:   t.Reason,
+		Category: t.Category,
+		Message:  t.Message,
+		Durable:  t.Durable,
+	}
+	if len(items) > 0 {
+		condition.Items = append([]string{}, items...)
+	}
+
+	return condition
+}
+
+//
+// A set of templates (by type).
+// Intended to be declared once per resource kind and
+// composed using Merge().
+//
+// Example:
+//     var Validation = condition.NewTemplateSet(
+//         InvalidCredentials,
+//         HostUnreachable)
+//     var PlanConditions = condition.NewTemplateSet(
+//         StorageClassMissing).Merge(Validation)
+//
+// +k8s:deepcopy-gen=false
+type TemplateSet map[string]Template
+
+//
+// Build a template set.
+func NewTemplateSet(templates ...Template) TemplateSet {
+	set := TemplateSet{}
+	for _, t := range templates {
+		set[t.Type] = t
+	}
+
+	return set
+}
+
+//
+// Get a new set containing this set and the others.
+// Templates in the others replace templates of the same type.
+func (s TemplateSet) Merge(others ...TemplateSet) TemplateSet {
+	set := TemplateSet{}
+	for _, t := range s {
+		set[t.Type] = t
+	}
+	for _, other := range others {
+		for _, t := range other {
+			set[t.Type] = t
+		}
+	}
+
+	return set
+}
+
+//
+// Get a template by type.
+func (s TemplateSet) Get(cndType string) (Template, bool) {
+	t, found := s[cndType]
+	return t, found
+}
+
+//
+// Get the (sorted) list of types.
+func (s TemplateSet) Types() []string {
+	list := []string{}
+	for name := range s {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
@@ -23,6 +23,10 @@ package condition
 func (in *Condition) DeepCopyInto(out *Condition) {
 	*out = *in
 	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.LastProbeTime != nil {
+		in, out := &in.LastProbeTime, &out.LastProbeTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
 		*out = make([]string, len(*in))
@@ -51,6 +55,24 @@ func (in *Conditions) DeepCopyInto(out *Conditions) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.savepoints != nil {
+		in, out := &in.savepoints, &out.savepoints
+		*out = make([][]Condition, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make([]Condition, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+		}
+	}
+	if in.migrated != nil {
+		in, out := &in.migrated, &out.migrated
+		*out = make([]Migration, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -63,3 +85,57 @@ func (in *Conditions) DeepCopy() *Conditions {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Key) DeepCopyInto(out *Key) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Key.
+func (in *Key) DeepCopy() *Key {
+	if in == nil {
+		return nil
+	}
+	out := new(Key)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Migration) DeepCopyInto(out *Migration) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Migration.
+func (in *Migration) DeepCopy() *Migration {
+	if in == nil {
+		return nil
+	}
+	out := new(Migration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in Params) DeepCopyInto(out *Params) {
+	{
+		in := &in
+		*out = make(Params, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+		return
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Params.
+func (in Params) DeepCopy() Params {
+	if in == nil {
+		return nil
+	}
+	out := new(Params)
+	in.DeepCopyInto(out)
+	return *out
+}
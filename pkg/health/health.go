@@ -0,0 +1,156 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/jortel/controller/pkg/condition"
+	"net/http"
+	"sync"
+)
+
+// Paths
+const (
+	LivePath       = "/healthz"
+	ReadyPath      = "/readyz"
+	ConditionsPath = "/conditions"
+)
+
+// Global
+var Status *Health
+
+//
+// Build globals.
+func init() {
+	Status = &Health{}
+}
+
+//
+// Process health.
+// Holds the (operational) conditions of the controller process
+// and serves them over HTTP.
+//   /healthz - Liveness. 503 when a condition with one of the
+//              `LiveCategories` is set. By default, only reports
+//              the process is alive; conditions such as bad
+//              credentials are not fixed by a restart.
+//   /readyz - Readiness. 503 when a blocker condition is set.
+//   /conditions - The conditions (JSON).
+//
+// Example:
+//     health.Status.SetCondition(condition.Condition{
+//         Type:     "CacheNotSynced",
+//         Status:   condition.True,
+//         Category: condition.Error,
+//         Message:  "The cache has not been synced.",
+//     })
+//     ...
+//     go http.ListenAndServe(":8081", health.Status.Handler())
+//
+type Health struct {
+	// Condition categories that fail the liveness probe.
+	// Default: none.
+	LiveCategories []string
+	conditions     condition.Conditions
+	mutex          sync.RWMutex
+}
+
+//
+// Set (add/update) the specified condition.
+func (h *Health) SetCondition(cnd condition.Condition) {
+	h.Update(func(conditions *condition.Conditions) {
+		conditions.SetCondition(cnd)
+	})
+}
+
+//
+// Delete conditions by type.
+func (h *Health) DeleteCondition(types ...string) {
+	h.Update(func(conditions *condition.Conditions) {
+		conditions.DeleteCondition(types...)
+	})
+}
+
+//
+// Update the conditions while holding the lock.
+// Intended for staging.
+func (h *Health) Update(fn func(*condition.Conditions)) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	fn(&h.conditions)
+}
+
+//
+// Get a copy of the conditions.
+func (h *Health) Conditions() condition.Conditions {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return *h.conditions.DeepCopy()
+}
+
+//
+// Get an HTTP handler serving all paths.
+func (h *Health) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(LivePath, h.Live)
+	mux.HandleFunc(ReadyPath, h.Ready)
+	mux.HandleFunc(ConditionsPath, h.List)
+	return mux
+}
+
+//
+// Liveness probe.
+func (h *Health) Live(w http.ResponseWriter, request *http.Request) {
+	h.probe(w, h.LiveCategories...)
+}
+
+//
+// Readiness probe.
+func (h *Health) Ready(w http.ResponseWriter, request *http.Request) {
+	h.probe(w, condition.Critical, condition.Error)
+}
+
+//
+// List the conditions.
+func (h *Health) List(w http.ResponseWriter, request *http.Request) {
+	list := h.expanded()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+//
+// Write the probe response.
+// Responds 503 when any condition with the specified
+// categories is set. The messages are written to the body.
+func (h *Health) probe(w http.ResponseWriter, categories ...string) {
+	conditions := h.Conditions()
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if len(categories) == 0 || !conditions.HasConditionCategory(categories...) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "ok")
+		return
+	}
+	catSet := map[string]bool{}
+	for _, name := range categories {
+		catSet[name] = true
+	}
+	w.WriteHeader(http.StatusServiceUnavailable)
+	for _, cnd := range h.expanded() {
+		if catSet[cnd.Category] && cnd.Status == condition.True {
+			fmt.Fprintf(w, "%s: %s\n", cnd.Type, cnd.Message)
+		}
+	}
+}
+
+//
+// Get a copy of the conditions with the
+// `Items` expanded into the `Message`.
+func (h *Health) expanded() []condition.Condition {
+	conditions := h.Conditions()
+	list := []condition.Condition{}
+	for _, cnd := range conditions.List {
+		cnd.ExpandItems()
+		list = append(list, cnd)
+	}
+
+	return list
+}
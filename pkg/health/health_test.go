@@ -0,0 +1,86 @@
+package health
+
+import (
+	"encoding/json"
+	"github.com/jortel/controller/pkg/condition"
+	"github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func get(h *Health, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestHealth(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	h := &Health{}
+
+	// Test nothing set.
+	g.Expect(get(h, LivePath).Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(get(h, ReadyPath).Code).To(gomega.Equal(http.StatusOK))
+
+	// Test Error.
+	h.SetCondition(condition.Condition{
+		Type:     "CacheNotSynced",
+		Status:   condition.True,
+		Category: condition.Error,
+		Message:  "Cache [] not synced.",
+		Items:    []string{"Secret"},
+	})
+	g.Expect(get(h, LivePath).Code).To(gomega.Equal(http.StatusOK))
+	w := get(h, ReadyPath)
+	g.Expect(w.Code).To(gomega.Equal(http.StatusServiceUnavailable))
+	g.Expect(w.Body.String()).To(gomega.ContainSubstring("CacheNotSynced: Cache [Secret] not synced."))
+
+	// Test Critical.
+	h.SetCondition(condition.Condition{
+		Type:     "InvalidCredentials",
+		Status:   condition.True,
+		Category: condition.Critical,
+		Message:  "Credentials not valid.",
+	})
+	g.Expect(get(h, LivePath).Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(get(h, ReadyPath).Code).To(gomega.Equal(http.StatusServiceUnavailable))
+
+	// Test Critical (live categories).
+	h.LiveCategories = []string{condition.Critical}
+	g.Expect(get(h, LivePath).Code).To(gomega.Equal(http.StatusServiceUnavailable))
+
+	// Test cleared.
+	h.DeleteCondition("CacheNotSynced", "InvalidCredentials")
+	g.Expect(get(h, LivePath).Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(get(h, ReadyPath).Code).To(gomega.Equal(http.StatusOK))
+}
+
+func TestHealthConditions(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	h := &Health{}
+	h.SetCondition(condition.Condition{
+		Type:     "Advice",
+		Status:   condition.True,
+		Category: condition.Advisory,
+		Message:  "Secrets [] not valid.",
+		Items:    []string{"s1", "s2"},
+	})
+
+	// Test
+	w := get(h, ConditionsPath)
+	list := []condition.Condition{}
+	err := json.Unmarshal(w.Body.Bytes(), &list)
+
+	// Validation
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(len(list)).To(gomega.Equal(1))
+	g.Expect(list[0].Type).To(gomega.Equal("Advice"))
+	g.Expect(list[0].Message).To(gomega.Equal("Secrets [s1,s2] not valid."))
+	g.Expect(h.Conditions().List[0].Message).To(gomega.Equal("Secrets [] not valid."))
+}
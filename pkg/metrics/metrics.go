@@ -0,0 +1,322 @@
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/jortel/controller/pkg/condition"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Metric names.
+const (
+	ConditionsMetric  = "controller_conditions"
+	TransitionsMetric = "controller_condition_transitions_total"
+	ResourcesMetric   = "controller_resources"
+	NotReadyMetric    = "controller_not_ready_seconds"
+)
+
+// The Prometheus text exposition format.
+const (
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// Default (not ready) histogram buckets (seconds).
+var DefaultBuckets = []float64{1, 5, 15, 30, 60, 300, 900, 1800, 3600}
+
+// Global
+var Collector *ConditionCollector
+
+//
+// Build globals.
+func init() {
+	Collector = New()
+}
+
+//
+// Condition series labels.
+type series struct {
+	Kind     string
+	Type     string
+	Category string
+	Status   string
+}
+
+//
+// Observed resource state.
+type resource struct {
+	// Conditions by type.
+	conditions map[string]condition.Condition
+	// When the resource became not ready.
+	notReadySince *time.Time
+}
+
+//
+// Not ready histogram (per kind).
+// The buckets are copied when the histogram is built.
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+//
+// In-process metrics collector fed by the `condition` package.
+// Implements condition.Observer and http.Handler.
+// Resources are observed by EndStagingConditionsFor() and
+// series are removed by condition.Forget() on delete.
+//   controller_conditions - (gauge) The number of resources with
+//       a condition by kind, type, category and status.
+//   controller_condition_transitions_total - (counter) The number
+//       of condition transitions by kind, type, category and status.
+//   controller_resources - (gauge) The number of resources by
+//       kind and readiness.
+//   controller_not_ready_seconds - (histogram) The time
+//       resources spent not ready by kind.
+//
+// Example:
+//     condition.AddObserver(metrics.Collector)
+//     http.Handle("/metrics", metrics.Collector)
+//     ...
+//     thing.Status.BeginStagingConditions()
+//     ...
+//     thing.Status.EndStagingConditionsFor(
+//         condition.Key{
+//             Kind:      "Thing",
+//             Namespace: thing.Namespace,
+//             Name:      thing.Name,
+//         })
+//
+type ConditionCollector struct {
+	// Not ready histogram buckets (seconds).
+	// Changes affect only histograms built afterwards.
+	Buckets []float64
+	// Observed resources.
+	resources map[condition.Key]*resource
+	// Transition counters.
+	transitions map[series]uint64
+	// Not ready histogram by kind.
+	notReady map[string]*histogram
+	// Time source.
+	now   func() time.Time
+	mutex sync.RWMutex
+}
+
+//
+// Build a new collector.
+func New() *ConditionCollector {
+	return &ConditionCollector{
+		Buckets:     DefaultBuckets,
+		resources:   map[condition.Key]*resource{},
+		transitions: map[series]uint64{},
+		notReady:    map[string]*histogram{},
+		now:         time.Now,
+	}
+}
+
+//
+// Observe the staged conditions of a resource.
+func (r *ConditionCollector) Observe(key condition.Key, conditions *condition.Conditions) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	now := r.now()
+	observed, found := r.resources[key]
+	if !found {
+		observed = &resource{
+			conditions: map[string]condition.Condition{},
+		}
+		r.resources[key] = observed
+	}
+	current := map[string]condition.Condition{}
+	for _, cnd := range conditions.List {
+		current[cnd.Type] = cnd
+		last, found := observed.conditions[cnd.Type]
+		if !found || last.Status != cnd.Status || last.Category != cnd.Category {
+			r.transitions[r.series(key, cnd)]++
+		}
+	}
+	observed.conditions = current
+	if conditions.IsReady() {
+		if observed.notReadySince != nil {
+			r.observeNotReady(key.Kind, now.Sub(*observed.notReadySince))
+			observed.notReadySince = nil
+		}
+	} else {
+		if observed.notReadySince == nil {
+			observed.notReadySince = &now
+		}
+	}
+}
+
+//
+// Forget a (deleted) resource.
+// The series are removed.
+func (r *ConditionCollector) Forget(key condition.Key) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.resources, key)
+}
+
+//
+// Reset all metrics.
+func (r *ConditionCollector) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.resources = map[condition.Key]*resource{}
+	r.transitions = map[series]uint64{}
+	r.notReady = map[string]*histogram{}
+}
+
+//
+// Serve the metrics using the Prometheus text exposition format.
+func (r *ConditionCollector) ServeHTTP(w http.ResponseWriter, request *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(r.Text()))
+}
+
+//
+// Render the metrics using the Prometheus text exposition format.
+func (r *ConditionCollector) Text() string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	out := &bytes.Buffer{}
+	// Conditions.
+	gauge := map[series]uint64{}
+	for key, observed := range r.resources {
+		for _, cnd := range observed.conditions {
+			gauge[r.series(key, cnd)]++
+		}
+	}
+	r.header(out, ConditionsMetric, "gauge", "The number of resources with a condition.")
+	for _, s := range r.sorted(gauge) {
+		fmt.Fprintf(out, "%s%s %d\n", ConditionsMetric, s.labels(), gauge[s])
+	}
+	// Transitions.
+	r.header(out, TransitionsMetric, "counter", "The number of condition transitions.")
+	for _, s := range r.sorted(r.transitions) {
+		fmt.Fprintf(out, "%s%s %d\n", TransitionsMetric, s.labels(), r.transitions[s])
+	}
+	// Resources.
+	ready := map[string]map[bool]uint64{}
+	for key, observed := range r.resources {
+		if _, found := ready[key.Kind]; !found {
+			ready[key.Kind] = map[bool]uint64{}
+		}
+		ready[key.Kind][observed.notReadySince == nil]++
+	}
+	r.header(out, ResourcesMetric, "gauge", "The number of resources by readiness.")
+	for _, kind := range r.kinds(ready) {
+		for _, b := range []bool{true, false} {
+			fmt.Fprintf(
+				out,
+				"%s{kind=%q,ready=%q} %d\n",
+				ResourcesMetric,
+				kind,
+				fmt.Sprintf("%t", b),
+				ready[kind][b])
+		}
+	}
+	// Not ready.
+	r.header(out, NotReadyMetric, "histogram", "The time resources spent not ready.")
+	kinds := []string{}
+	for kind := range r.notReady {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		h := r.notReady[kind]
+		for i, le := range h.buckets {
+			fmt.Fprintf(
+				out,
+				"%s_bucket{kind=%q,le=%q} %d\n",
+				NotReadyMetric,
+				kind,
+				fmt.Sprintf("%g", le),
+				h.counts[i])
+		}
+		fmt.Fprintf(out, "%s_bucket{kind=%q,le=%q} %d\n", NotReadyMetric, kind, "+Inf", h.count)
+		fmt.Fprintf(out, "%s_sum{kind=%q} %g\n", NotReadyMetric, kind, h.sum)
+		fmt.Fprintf(out, "%s_count{kind=%q} %d\n", NotReadyMetric, kind, h.count)
+	}
+
+	return out.String()
+}
+
+//
+// Record time spent not ready.
+func (r *ConditionCollector) observeNotReady(kind string, duration time.Duration) {
+	h, found := r.notReady[kind]
+	if !found {
+		h = &histogram{
+			buckets: append([]float64{}, r.Buckets...),
+			counts:  make([]uint64, len(r.Buckets)),
+		}
+		r.notReady[kind] = h
+	}
+	seconds := duration.Seconds()
+	for i, le := range h.buckets {
+		if seconds <= le {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+//
+// Build the series for a condition.
+func (r *ConditionCollector) series(key condition.Key, cnd condition.Condition) series {
+	return series{
+		Kind:     key.Kind,
+		Type:     cnd.Type,
+		Category: cnd.Category,
+		Status:   cnd.Status,
+	}
+}
+
+//
+// Write the metric HELP and TYPE.
+func (r *ConditionCollector) header(out *bytes.Buffer, name, kind, help string) {
+	fmt.Fprintf(out, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(out, "# TYPE %s %s\n", name, kind)
+}
+
+//
+// Sorted series.
+func (r *ConditionCollector) sorted(m map[series]uint64) []series {
+	list := []series{}
+	for s := range m {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].labels() < list[j].labels()
+	})
+
+	return list
+}
+
+//
+// Sorted kinds.
+func (r *ConditionCollector) kinds(m map[string]map[bool]uint64) []string {
+	list := []string{}
+	for kind := range m {
+		list = append(list, kind)
+	}
+	sort.Strings(list)
+	return list
+}
+
+//
+// Format labels.
+func (s series) labels() string {
+	return fmt.Sprintf(
+		"{kind=%q,type=%q,category=%q,status=%q}",
+		s.Kind,
+		s.Type,
+		s.Category,
+		s.Status)
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"github.com/jortel/controller/pkg/condition"
+	"github.com/onsi/gomega"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCollector(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	now := time.Now()
+	collector := New()
+	collector.now = func() time.Time {
+		return now
+	}
+	condition.AddObserver(collector)
+	defer condition.RemoveObserver(collector)
+	key := condition.Key{
+		Kind:      "Thing",
+		Namespace: "ns0",
+		Name:      "joe",
+	}
+	conditions := condition.Conditions{}
+
+	// Test not ready.
+	conditions.BeginStagingConditions()
+	conditions.SetCondition(condition.Condition{
+		Type:     "HostNotFound",
+		Status:   condition.True,
+		Category: condition.Error,
+	})
+	conditions.SetReady(!conditions.HasBlockerCondition(), "Ready.")
+	conditions.EndStagingConditionsFor(key)
+
+	// Validation
+	text := collector.Text()
+	g.Expect(text).To(gomega.ContainSubstring(
+		`controller_conditions{kind="Thing",type="HostNotFound",category="Error",status="True"} 1`))
+	g.Expect(text).To(gomega.ContainSubstring(
+		`controller_condition_transitions_total{kind="Thing",type="HostNotFound",category="Error",status="True"} 1`))
+	g.Expect(text).To(gomega.ContainSubstring(
+		`controller_resources{kind="Thing",ready="false"} 1`))
+
+	// Test ready.
+	now = now.Add(time.Second * 10)
+	conditions.BeginStagingConditions()
+	conditions.SetReady(!conditions.HasBlockerCondition(), "Ready.")
+	conditions.EndStagingConditionsFor(key)
+
+	// Validation
+	text = collector.Text()
+	g.Expect(text).NotTo(gomega.ContainSubstring(`type="HostNotFound"}`))
+	g.Expect(text).To(gomega.ContainSubstring(
+		`controller_conditions{kind="Thing",type="Ready",category="Required",status="True"} 1`))
+	g.Expect(text).To(gomega.ContainSubstring(
+		`controller_resources{kind="Thing",ready="true"} 1`))
+	g.Expect(text).To(gomega.ContainSubstring(
+		`controller_not_ready_seconds_bucket{kind="Thing",le="5"} 0`))
+	g.Expect(text).To(gomega.ContainSubstring(
+		`controller_not_ready_seconds_bucket{kind="Thing",le="15"} 1`))
+	g.Expect(text).To(gomega.ContainSubstring(
+		`controller_not_ready_seconds_sum{kind="Thing"} 10`))
+
+	// Test buckets changed.
+	collector.Buckets = append(collector.Buckets, 7200, 14400)
+
+	// Validation
+	text = collector.Text()
+	g.Expect(text).NotTo(gomega.ContainSubstring(`le="7200"`))
+
+	// Test deleted.
+	condition.Forget(key)
+
+	// Validation
+	text = collector.Text()
+	g.Expect(text).NotTo(gomega.ContainSubstring(`controller_conditions{`))
+	g.Expect(text).NotTo(gomega.ContainSubstring(`controller_resources{`))
+}
+
+func TestCollectorServeHTTP(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	collector := New()
+	w := httptest.NewRecorder()
+
+	// Test
+	collector.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// Validation
+	g.Expect(w.Code).To(gomega.Equal(http.StatusOK))
+	g.Expect(w.Header().Get("Content-Type")).To(gomega.Equal(ContentType))
+	g.Expect(w.Body.String()).To(gomega.ContainSubstring("# TYPE controller_conditions gauge"))
+}
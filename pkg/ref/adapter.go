@@ -0,0 +1,103 @@
+package ref
+
+import (
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"reflect"
+)
+
+//
+// Implemented by custom reference types.
+// The target `Kind` and `APIVersion` are optional. When not
+// specified, the kind is determined by the `ref` tag. An empty
+// `Namespace` implies the owner's namespace (local ref).
+//
+// Example:
+//     type ThingRef struct {
+//         Namespace string `json:"namespace,omitempty"`
+//         Name      string `json:"name"`
+//     }
+//
+//     func (r ThingRef) RefTarget() ref.Target {
+//         return ref.Target{Namespace: r.Namespace, Name: r.Name}
+//     }
+//
+type Referencer interface {
+	RefTarget() Target
+}
+
+//
+// Adapts a reference type to a Target.
+// The target `Kind` and `APIVersion` are optional. When not
+// specified, the kind is determined by the `ref` tag.
+// Returns `local` when the reference implies the owner's namespace.
+type Adapter func(value interface{}) (target Target, local bool)
+
+//
+// Registered adapters by (reference) type.
+// Built-in adapters are provided for:
+//   - v1.ObjectReference
+//   - v1.LocalObjectReference (local)
+//   - v1.TypedLocalObjectReference (local)
+//   - v1.SecretReference
+var Adapters = map[reflect.Type]Adapter{
+	reflect.TypeOf(v1.ObjectReference{}): func(value interface{}) (Target, bool) {
+		ref := value.(v1.ObjectReference)
+		return Target{
+			APIVersion: ref.APIVersion,
+			Kind:       ref.Kind,
+			Namespace:  ref.Namespace,
+			Name:       ref.Name,
+		}, false
+	},
+	reflect.TypeOf(v1.LocalObjectReference{}): func(value interface{}) (Target, bool) {
+		ref := value.(v1.LocalObjectReference)
+		return Target{
+			Name: ref.Name,
+		}, true
+	},
+	reflect.TypeOf(v1.TypedLocalObjectReference{}): func(value interface{}) (Target, bool) {
+		ref := value.(v1.TypedLocalObjectReference)
+		gv := schema.GroupVersion{}
+		if ref.APIGroup != nil {
+			gv.Group = *ref.APIGroup
+		}
+		return Target{
+			APIVersion: gv.String(),
+			Kind:       ref.Kind,
+			Name:       ref.Name,
+		}, true
+	},
+	reflect.TypeOf(v1.SecretReference{}): func(value interface{}) (Target, bool) {
+		ref := value.(v1.SecretReference)
+		return Target{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		}, false
+	},
+}
+
+//
+// Adapt the value to a target.
+// Returns `adapted` false when the value is not a reference.
+func adapt(rv reflect.Value) (target Target, local bool, adapted bool) {
+	if !rv.CanInterface() {
+		return
+	}
+	if adapter, found := Adapters[rv.Type()]; found {
+		target, local = adapter(rv.Interface())
+		adapted = true
+		return
+	}
+	referencer, cast := rv.Interface().(Referencer)
+	if !cast && rv.CanAddr() {
+		referencer, cast = rv.Addr().Interface().(Referencer)
+	}
+	if cast {
+		target = referencer.RefTarget()
+		local = target.Namespace == ""
+		adapted = true
+	}
+
+	return
+}
@@ -0,0 +1,146 @@
+package ref
+
+import (
+	"fmt"
+	"github.com/jortel/controller/pkg/condition"
+	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Reasons
+const (
+	Waiting = "Waiting"
+)
+
+//
+// Dependency waiter.
+// Declares dependencies of the owner on other objects and
+// manages the standard `DependencyNotReady` condition. The
+// dependencies (targets) not ready are added to the `Map` as
+// dependency mappings so that changes to them re-queue the owner.
+// Dependencies must be declared on each reconcile (while staging)
+// followed by SetCondition().
+//
+// Example:
+//     waiter := ref.NewWaiter(refMap, thing, &thing.Status.Conditions)
+//     provider := &Provider{}
+//     err := client.Get(ctx, key, provider)
+//     ...
+//     ready := waiter.WaitFor(thing.Spec.Provider, provider, &provider.Status.Conditions)
+//     ...
+//     waiter.SetCondition()
+//
+type Waiter struct {
+	// The ref map. Defaults to `Map`.
+	Map *RefMap
+	// The owner (waiter).
+	// Must be keyed the same as by the EventMapper.
+	// See: NewWaiter().
+	Owner Owner
+	// The owner conditions.
+	Conditions *condition.Conditions
+	// Dependencies not ready.
+	waiting []Target
+}
+
+//
+// Build a waiter.
+// The owner is keyed (GVK) using the map resolver
+// the same as by the EventMapper.
+func NewWaiter(m *RefMap, owner meta.Object, conditions *condition.Conditions) *Waiter {
+	w := &Waiter{
+		Map:        m,
+		Conditions: conditions,
+	}
+	w.Owner = w.refMap().OwnerOf(owner)
+
+	return w
+}
+
+//
+// Wait for a dependency to be `Ready`.
+// The `object` is the dependency (or an object of the same
+// kind) used to resolve the kind when not specified by the `ref`.
+// The namespace defaults to the namespace of the owner.
+// The `dependency` conditions are nil when not found.
+// Returns true when the dependency is ready. Returns false
+// when the `ref` is not set (nothing to wait for).
+func (w *Waiter) WaitFor(ref *v1.ObjectReference, object runtime.Object, dependency *condition.Conditions) bool {
+	if ref == nil || ref.Name == "" {
+		return false
+	}
+	resolver := w.refMap().resolver()
+	gvk := resolver.RefKind(ref.APIVersion, ref.Kind)
+	if ref.Kind == "" && object != nil {
+		gvk = resolver.ObjectKind(object)
+	}
+	namespace := ref.Namespace
+	if resolver.ClusterScoped(gvk) {
+		namespace = ""
+	} else {
+		if namespace == "" {
+			namespace = w.Owner.Namespace
+		}
+	}
+	target := Target{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  namespace,
+		Name:       ref.Name,
+	}
+	ready := dependency != nil && dependency.IsReady()
+	if ready {
+		return true
+	}
+	for _, waiting := range w.waiting {
+		if waiting == target {
+			return false
+		}
+	}
+	w.waiting = append(w.waiting, target)
+	return false
+}
+
+//
+// Set the `DependencyNotReady` condition for all of the
+// dependencies (declared by WaitFor()) that are not ready.
+// The condition is deleted when all are ready. The dependency
+// mappings of the owner are replaced by those not ready.
+// Should be called once per reconcile.
+func (w *Waiter) SetCondition() {
+	defer func() {
+		w.waiting = nil
+	}()
+	w.refMap().UpdateDependencies(w.Owner, w.waiting...)
+	if len(w.waiting) == 0 {
+		w.Conditions.DeleteCondition(condition.DependencyNotReady)
+		return
+	}
+	items := []string{}
+	for _, target := range w.waiting {
+		item := fmt.Sprintf("%s/%s", target.Kind, target.Name)
+		if target.Namespace != "" {
+			item = fmt.Sprintf("%s/%s/%s", target.Kind, target.Namespace, target.Name)
+		}
+		items = append(items, item)
+	}
+	w.Conditions.SetCondition(condition.Condition{
+		Type:     condition.DependencyNotReady,
+		Status:   condition.True,
+		Reason:   Waiting,
+		Category: condition.Error,
+		Message:  "Waiting for dependencies [] to be ready.",
+		Items:    items,
+	})
+}
+
+//
+// The ref map.
+func (w *Waiter) refMap() *RefMap {
+	if w.Map != nil {
+		return w.Map
+	}
+
+	return Map
+}
@@ -0,0 +1,243 @@
+package ref
+
+import (
+	"fmt"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+//
+// A reference found in an object.
+type Ref struct {
+	// The field path. Eg: spec.refs[0]
+	Path string
+	// The (resolved) ref target.
+	Target Target
+	// The kind declared by the tag (when specified).
+	Declared schema.GroupVersionKind
+}
+
+//
+// Ref extractor.
+// Recursively inspects structs, pointers, slices, arrays and
+// maps for `ref` tagged references. A tag on a slice, array or
+// map field applies to the elements. Embedded structs are
+// inlined. Cycles are detected and not followed.
+// References are adapted to targets using the registered
+// `Adapters` and the `Referencer` interface. Local references
+// (no namespace) resolve to the owner's namespace.
+// The tag may be empty (ref:"") for references that specify
+// the kind. Eg: v1.TypedLocalObjectReference.
+// Cluster-scoped targets are detected using the RESTMapper
+// (when configured) or the `cluster` tag option.
+//
+// Example:
+//     type Spec struct {
+//         Provider *v1.ObjectReference            `json:"provider" ref:"Provider"`
+//         Hosts    []v1.ObjectReference           `json:"hosts" ref:"Host"`
+//         Secrets  map[string]*v1.ObjectReference `json:"secrets" ref:"Secret"`
+//         Config   v1.LocalObjectReference        `json:"config" ref:"ConfigMap"`
+//         Source   *v1.TypedLocalObjectReference  `json:"source" ref:""`
+//         Storage  *v1.ObjectReference            `json:"storage" ref:"StorageClass,cluster"`
+//     }
+//
+// Found paths: spec.provider, spec.hosts[0], spec.secrets[key],
+// spec.config, spec.source, spec.storage.
+//
+type Extractor struct {
+	// The kind resolver.
+	Resolver *Resolver
+	// The owner namespace.
+	namespace string
+	// Visited structs.
+	visited map[visit]bool
+	// Found refs.
+	found []Ref
+}
+
+//
+// A visited (addressable) struct.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+//
+// A field (context) being walked.
+type field struct {
+	path   string
+	tag    string
+	tagged bool
+}
+
+//
+// Extract the refs.
+func (r *Extractor) Extract(object interface{}) []Ref {
+	r.visited = map[visit]bool{}
+	r.found = []Ref{}
+	r.namespace = ""
+	if object == nil {
+		return r.found
+	}
+	if owner, cast := object.(interface{ GetNamespace() string }); cast {
+		r.namespace = owner.GetNamespace()
+	}
+	r.walk(reflect.ValueOf(object), field{})
+	return r.found
+}
+
+//
+// Walk the value.
+func (r *Extractor) walk(rv reflect.Value, f field) {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if rv.IsNil() {
+			return
+		}
+		r.walk(rv.Elem(), f)
+		return
+	}
+	if f.tagged {
+		if target, local, adapted := adapt(rv); adapted {
+			r.add(target, local, f)
+			return
+		}
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		if rv.CanAddr() {
+			key := visit{ptr: rv.UnsafeAddr(), typ: rv.Type()}
+			if r.visited[key] {
+				return
+			}
+			r.visited[key] = true
+		}
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			ft := rt.Field(i)
+			if ft.PkgPath != "" && !ft.Anonymous {
+				continue
+			}
+			next := field{path: f.path}
+			if !ft.Anonymous {
+				next.path = r.join(f.path, r.fieldName(ft))
+			}
+			next.tag, next.tagged = ft.Tag.Lookup(Tag)
+			r.walk(rv.Field(i), next)
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return
+		}
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < rv.Len(); i++ {
+			next := f
+			next.path = fmt.Sprintf("%s[%d]", f.path, i)
+			r.walk(rv.Index(i), next)
+		}
+	case reflect.Map:
+		if rv.IsNil() {
+			return
+		}
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+		})
+		for _, key := range keys {
+			next := f
+			next.path = fmt.Sprintf("%s[%v]", f.path, key.Interface())
+			r.walk(rv.MapIndex(key), next)
+		}
+	}
+}
+
+//
+// Add a found (adapted) ref.
+// The kind specified by the reference has precedence over the tag.
+// Cluster-scoped targets have no namespace.
+func (r *Extractor) add(target Target, local bool, f field) {
+	kind, options := r.parseTag(f.tag)
+	declared := schema.GroupVersionKind{}
+	if kind != "" {
+		declared = r.resolver().TagKind(kind)
+	}
+	gvk := declared
+	if target.Kind != "" {
+		gvk = r.resolver().RefKind(target.APIVersion, target.Kind)
+	}
+	if gvk.Kind == "" || target.Name == "" {
+		return
+	}
+	if options[ClusterOption] || r.resolver().ClusterScoped(gvk) {
+		target.Namespace = ""
+	} else {
+		if local && target.Namespace == "" {
+			target.Namespace = r.namespace
+		}
+		if target.Namespace == "" {
+			return
+		}
+	}
+	r.found = append(
+		r.found,
+		Ref{
+			Path: f.path,
+			Target: Target{
+				APIVersion: gvk.GroupVersion().String(),
+				Kind:       gvk.Kind,
+				Namespace:  target.Namespace,
+				Name:       target.Name,
+			},
+			Declared: declared,
+		})
+}
+
+//
+// Parse the `ref` tag.
+// Returns the kind and options.
+func (r *Extractor) parseTag(tag string) (kind string, options map[string]bool) {
+	options = map[string]bool{}
+	p := strings.Split(tag, ",")
+	kind = strings.TrimSpace(p[0])
+	for _, option := range p[1:] {
+		options[strings.TrimSpace(option)] = true
+	}
+
+	return
+}
+
+//
+// The field name.
+// The json name is used when specified.
+func (r *Extractor) fieldName(ft reflect.StructField) string {
+	name := strings.Split(ft.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		name = ft.Name
+	}
+
+	return name
+}
+
+//
+// Join the path.
+func (r *Extractor) join(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+//
+// The kind resolver.
+func (r *Extractor) resolver() *Resolver {
+	if r.Resolver != nil {
+		return r.Resolver
+	}
+
+	return defaultResolver
+}
@@ -0,0 +1,89 @@
+package ref
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+//
+// Enable tracking of deleted targets. See: Gone().
+func (r *RefMap) TrackGone() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.trackGone = true
+}
+
+//
+// A mapped target has been deleted.
+// When tracking is enabled, the target is recorded as `gone` for
+// each mapped owner. The owners are returned as requests to be
+// enqueued. The owner reconcile should use Gone() to learn which
+// targets are gone rather than guessing from a failed `Get`.
+func (r *RefMap) TargetGone(a handler.MapObject) []reconcile.Request {
+	target := r.targetOf(a)
+	owners := r.findOwners(target)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.trackGone {
+		if r.gone == nil {
+			r.gone = map[Owner]map[Target]bool{}
+		}
+		for _, owner := range owners {
+			targets, found := r.gone[owner]
+			if !found {
+				targets = map[Target]bool{}
+				r.gone[owner] = targets
+			}
+			targets[target] = true
+		}
+	}
+
+	return r.requests(owners)
+}
+
+//
+// Get (and clear) the deleted targets of the owner(s) accepted
+// by the owner kind filter. Requires tracking. See: TrackGone().
+//
+// Example:
+//     refMap := ref.NewMap(&Thing{})
+//     refMap.TrackGone()
+//     ...
+//     func (r *Reconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+//         for _, target := range r.refMap.Gone(request) {
+//             ...
+//         }
+//     }
+func (r *RefMap) Gone(request reconcile.Request) []Target {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	list := []Target{}
+	for owner, targets := range r.gone {
+		if owner.Namespace != request.Namespace ||
+			owner.Name != request.Name ||
+			!r.Accepted(owner) {
+			continue
+		}
+		for target := range targets {
+			list = append(list, target)
+		}
+		delete(r.gone, owner)
+	}
+
+	return list
+}
+
+//
+// A deleted target has been (re)created.
+// The target is no longer `gone` for any owner.
+func (r *RefMap) targetRestored(a handler.MapObject) {
+	target := r.targetOf(a)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for owner, targets := range r.gone {
+		delete(targets, target)
+		if len(targets) == 0 {
+			delete(r.gone, owner)
+		}
+	}
+}
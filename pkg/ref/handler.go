@@ -1,40 +1,86 @@
 package ref
 
 import (
-	"k8s.io/apimachinery/pkg/types"
-	"reflect"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
-	"strings"
 )
 
 //
 // Impl the handler interface.
+// Deprecated: Use the GetRequests() method of a controller
+// specific RefMap. See: NewMap().
 func GetRequests(a handler.MapObject, source interface{}) []reconcile.Request {
-	target := Target{
-		Kind:      ToKind(a.Object),
-		Name:      a.Meta.GetName(),
-		Namespace: a.Meta.GetNamespace(),
+	return Map.GetRequests(a)
+}
+
+//
+// Target event handler.
+// Enqueues the owners mapped to the target.
+// Deleted targets are reported to owners. See: RefMap.TrackGone().
+//
+// Example:
+//     err = cnt.Watch(
+//         &source.Kind{Type: &v1.Secret{}},
+//         refMap.Handler())
+//
+type EventHandler struct {
+	Map *RefMap
+}
+
+//
+// Create event.
+func (h *EventHandler) Create(event event.CreateEvent, q workqueue.RateLimitingInterface) {
+	if event.Meta != nil {
+		h.Map.targetRestored(handler.MapObject{Meta: event.Meta, Object: event.Object})
 	}
-	list := []reconcile.Request{}
-	for _, owner := range Map.Find(target) {
-		list = append(
-			list,
-			reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Namespace: owner.Namespace,
-					Name:      owner.Name,
-				},
-			})
+	h.enqueue(q, event.Meta, event.Object)
+}
+
+//
+// Update event.
+func (h *EventHandler) Update(event event.UpdateEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, event.MetaOld, event.ObjectOld)
+	h.enqueue(q, event.MetaNew, event.ObjectNew)
+}
+
+//
+// Delete event.
+func (h *EventHandler) Delete(event event.DeleteEvent, q workqueue.RateLimitingInterface) {
+	if event.Meta == nil {
+		return
+	}
+	list := h.Map.TargetGone(
+		handler.MapObject{
+			Meta:   event.Meta,
+			Object: event.Object,
+		})
+	for _, request := range list {
+		q.Add(request)
 	}
+}
 
-	return list
+//
+// Generic event.
+func (h *EventHandler) Generic(event event.GenericEvent, q workqueue.RateLimitingInterface) {
+	h.enqueue(q, event.Meta, event.Object)
 }
 
 //
-// Determine the resource Kind.
-func ToKind(resource interface{}) string {
-	t := reflect.TypeOf(resource).String()
-	p := strings.SplitN(t, ".", 2)
-	return string(p[len(p)-1])
+// Enqueue the owners of the target.
+func (h *EventHandler) enqueue(q workqueue.RateLimitingInterface, meta v1.Object, object runtime.Object) {
+	if meta == nil {
+		return
+	}
+	list := h.Map.GetRequests(
+		handler.MapObject{
+			Meta:   meta,
+			Object: object,
+		})
+	for _, request := range list {
+		q.Add(request)
+	}
 }
@@ -0,0 +1,182 @@
+package ref
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"strings"
+	"sync"
+)
+
+//
+// Kind resolver.
+// Resolves the GroupVersionKind (GVK) of objects and `ref` tags
+// through the scheme. Kinds resolve to the preferred version of
+// the group so that objects and tags resolve to the same GVK.
+// Kinds not registered in the scheme resolve to the (short) kind
+// with an empty group and version.
+//
+// The `ref` tag syntax is: Kind[.group][,option]
+// Example:
+//     `ref:"Secret"`               (short form)
+//     `ref:"Thing.example.io"`     (group qualified)
+//     `ref:"StorageClass,cluster"` (cluster-scoped)
+//
+// The short form resolves to the core group when the kind
+// is defined there; otherwise, to the only group that defines it.
+//
+type Resolver struct {
+	// The scheme. Defaults to `Scheme`.
+	Scheme *runtime.Scheme
+	// The (optional) REST mapper used to detect the scope
+	// of kinds. Defaults to `RESTMapper`.
+	RESTMapper meta.RESTMapper
+	// Resolved (short form) kinds by scheme.
+	shortKinds map[shortKey]schema.GroupVersionKind
+	mutex      sync.Mutex
+}
+
+//
+// Resolved (short form) kind key.
+type shortKey struct {
+	scheme *runtime.Scheme
+	kind   string
+}
+
+//
+// Resolve the GVK of an object.
+func (r *Resolver) ObjectKind(object interface{}) schema.GroupVersionKind {
+	if rtObject, cast := object.(runtime.Object); cast {
+		gvk, err := apiutil.GVKForObject(rtObject, r.scheme())
+		if err == nil {
+			return r.preferred(gvk.GroupKind())
+		}
+	}
+
+	return schema.GroupVersionKind{Kind: ToKind(object)}
+}
+
+//
+// Resolve the GVK of a `ref` tag.
+func (r *Resolver) TagKind(tag string) schema.GroupVersionKind {
+	tag = strings.Split(tag, ",")[0]
+	p := strings.SplitN(strings.TrimSpace(tag), ".", 2)
+	if len(p) == 2 {
+		return r.preferred(schema.GroupKind{Group: p[1], Kind: p[0]})
+	}
+
+	return r.short(p[0])
+}
+
+//
+// Resolve the GVK of an `apiVersion` and `kind`.
+// The version is normalized to the preferred version.
+func (r *Resolver) RefKind(apiVersion, kind string) schema.GroupVersionKind {
+	if apiVersion == "" {
+		return r.short(kind)
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return schema.GroupVersionKind{Kind: kind}
+	}
+
+	return r.preferred(schema.GroupKind{Group: gv.Group, Kind: kind})
+}
+
+//
+// Get whether the kind is cluster-scoped.
+// Requires a REST mapper.
+func (r *Resolver) ClusterScoped(gvk schema.GroupVersionKind) bool {
+	mapper := r.RESTMapper
+	if mapper == nil {
+		mapper = RESTMapper
+	}
+	if mapper == nil || gvk.Version == "" {
+		return false
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return false
+	}
+
+	return mapping.Scope.Name() == meta.RESTScopeNameRoot
+}
+
+//
+// Resolve the preferred version of a group kind.
+// Unknown kinds resolve to the kind only.
+func (r *Resolver) preferred(gk schema.GroupKind) schema.GroupVersionKind {
+	scheme := r.scheme()
+	for _, gv := range scheme.PrioritizedVersionsForGroup(gk.Group) {
+		gvk := gv.WithKind(gk.Kind)
+		if scheme.Recognizes(gvk) {
+			return gvk
+		}
+	}
+
+	return schema.GroupVersionKind{Kind: gk.Kind}
+}
+
+//
+// Resolve a (short form) kind.
+// Prefers the core group; otherwise, the only group defining
+// the kind. Unknown or ambiguous kinds resolve to the kind only.
+// Resolved kinds are cached (by scheme) so the scheme must be
+// fully populated before use.
+func (r *Resolver) short(kind string) schema.GroupVersionKind {
+	key := shortKey{scheme: r.scheme(), kind: kind}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if gvk, found := r.shortKinds[key]; found {
+		return gvk
+	}
+	gvk := r.resolveShort(kind)
+	if r.shortKinds == nil {
+		r.shortKinds = map[shortKey]schema.GroupVersionKind{}
+	}
+	r.shortKinds[key] = gvk
+
+	return gvk
+}
+
+//
+// Resolve a (short form) kind using the scheme.
+func (r *Resolver) resolveShort(kind string) schema.GroupVersionKind {
+	groups := map[string]bool{}
+	for gvk := range r.scheme().AllKnownTypes() {
+		if gvk.Kind == kind && gvk.Version != runtime.APIVersionInternal {
+			groups[gvk.Group] = true
+		}
+	}
+	if groups[""] {
+		return r.preferred(schema.GroupKind{Kind: kind})
+	}
+	if len(groups) == 1 {
+		for group := range groups {
+			return r.preferred(schema.GroupKind{Group: group, Kind: kind})
+		}
+	}
+
+	return schema.GroupVersionKind{Kind: kind}
+}
+
+//
+// The scheme.
+func (r *Resolver) scheme() *runtime.Scheme {
+	if r.Scheme != nil {
+		return r.Scheme
+	}
+
+	return Scheme
+}
+
+//
+// Determine the resource Kind (Go type name).
+// Used when the kind cannot be resolved through the scheme.
+func ToKind(resource interface{}) string {
+	t := reflect.TypeOf(resource).String()
+	p := strings.SplitN(t, ".", 2)
+	return string(p[len(p)-1])
+}
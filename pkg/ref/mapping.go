@@ -2,6 +2,12 @@ package ref
 
 import (
 	"k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sync"
 )
 
@@ -13,23 +19,123 @@ type Target v1.ObjectReference
 
 //
 // A 1-n mapping of Target => [Owner, ...].
+// A reverse index of Owner => [Target, ...] is maintained.
+// Mappings (edges) found in `ref` tagged fields and dependency
+// mappings declared by a Waiter are indexed separately so that
+// updating the `ref` mappings does not affect dependencies.
+// The `Content` must not be modified directly. Use Reset()
+// to clear all mappings. For compatibility, the index is
+// rebuilt when the `Content` is replaced.
+// Each controller should construct its own map using NewMap()
+// so that only owners of the controller's kind are mapped
+// and enqueued.
+//
+// Example:
+//     refMap := ref.NewMap(&Thing{})
+//     ...
+//     err = cnt.Watch(
+//         &source.Kind{Type: &v1.Secret{}},
+//         &handler.EnqueueRequestsFromMapFunc{
+//             ToRequests: handler.ToRequestsFunc(refMap.GetRequests),
+//         })
+//
 type RefMap struct {
 	Content map[Target]map[Owner]bool
-	mutex   sync.RWMutex
+	// Owner kind filter.
+	// When set, only owners of this kind are mapped.
+	OwnerKind schema.GroupKind
+	// The kind resolver used by the map, mapper, handler
+	// and predicate. Defaults to using `Scheme` and `RESTMapper`.
+	Resolver *Resolver
+	// Reverse index (ref mappings).
+	owners map[Owner]map[Target]bool
+	// Reverse index (dependency mappings).
+	dependencies map[Owner]map[Target]bool
+	// The (Content) map that was indexed.
+	// Referenced so that the map (address) cannot be reused.
+	indexed map[Target]map[Owner]bool
+	// Deleted targets by owner. See: TrackGone().
+	gone      map[Owner]map[Target]bool
+	trackGone bool
+	// Targets no longer mapped to any owner. See: TrackReleased().
+	released      map[Target]bool
+	trackReleased bool
+	// All owners have been mapped. See: SetSynced().
+	synced bool
+	mutex  sync.RWMutex
+}
+
+//
+// Build a map of owners of the specified kind.
+// The `owner` is an object (prototype) of the owner kind
+// resolved through the (optional) `resolver`. When nil, owners
+// of all kinds are mapped.
+func NewMap(owner interface{}, resolver ...*Resolver) *RefMap {
+	m := &RefMap{
+		Content: map[Target]map[Owner]bool{},
+	}
+	if len(resolver) > 0 {
+		m.Resolver = resolver[0]
+	}
+	if owner != nil {
+		m.OwnerKind = m.resolver().ObjectKind(owner).GroupKind()
+	}
+
+	return m
+}
+
+//
+// Build an event mapper for the map.
+func (r *RefMap) Mapper() *EventMapper {
+	return &EventMapper{Map: r}
+}
+
+//
+// Build the owner (key) of an object.
+// The owner is keyed (GVK) using the map resolver
+// the same as by the EventMapper.
+func (r *RefMap) OwnerOf(object meta.Object) Owner {
+	return r.Mapper().owner(object, object.GetNamespace(), object.GetName())
 }
 
 //
 // Add mapping of a ref-owner to a ref-target.
 func (r *RefMap) Add(owner Owner, target Target) {
+	if !r.Accepted(owner) {
+		return
+	}
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
-	owners, found := r.Content[target]
-	if !found {
-		owners = map[Owner]bool{}
-		r.Content[target] = owners
+	r.index()
+	r.add(owner, target)
+}
+
+//
+// Update the (ref) mappings of an owner.
+// Only the changed mappings (edges) are added or deleted.
+// Dependency mappings are not affected.
+func (r *RefMap) Update(owner Owner, targets ...Target) {
+	if !r.Accepted(owner) {
+		return
 	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.index()
+	r.update(r.owners, owner, targets)
+}
 
-	r.Content[target][owner] = true
+//
+// Update the dependency mappings of an owner.
+// Only the changed mappings (edges) are added or deleted.
+// Ref mappings are not affected. See: Waiter.
+func (r *RefMap) UpdateDependencies(owner Owner, targets ...Target) {
+	if !r.Accepted(owner) {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.index()
+	r.update(r.dependencies, owner, targets)
 }
 
 //
@@ -37,22 +143,24 @@ func (r *RefMap) Add(owner Owner, target Target) {
 func (r *RefMap) Delete(owner Owner, target Target) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
-	owners, found := r.Content[target]
-	if found {
-		delete(owners, owner)
-	}
-	r.Prune()
+	r.index()
+	r.delete(owner, target)
 }
 
 //
 // Delete all mappings to an owner.
+// Includes dependency mappings.
 func (r *RefMap) DeleteOwner(owner Owner) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
-	for _, owners := range r.Content {
-		delete(owners, owner)
+	r.index()
+	for target := range r.owners[owner] {
+		r.delete(owner, target)
+	}
+	for target := range r.dependencies[owner] {
+		r.unlink(r.dependencies, owner, target)
 	}
-	r.Prune()
+	delete(r.gone, owner)
 }
 
 //
@@ -83,12 +191,302 @@ func (r *RefMap) Find(target Target) []Owner {
 	return list
 }
 
+//
+// Find all targets mapped to the owner.
+// Includes dependency mappings. See: OwnerOf().
+func (r *RefMap) FindTargets(owner Owner) []Target {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.index()
+	list := []Target{}
+	for target := range r.owners[owner] {
+		list = append(list, target)
+	}
+	for target := range r.dependencies[owner] {
+		if !r.owners[owner][target] {
+			list = append(list, target)
+		}
+	}
+
+	return list
+}
+
+//
+// Enable tracking of targets released by the last owner.
+// See: Released(), Protector.
+func (r *RefMap) TrackReleased() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.trackReleased = true
+}
+
+//
+// Get the targets released by the last owner.
+// A target is released when no longer mapped to any owner.
+// Released targets are tracked until mapped again or handled
+// by the Protector. Requires tracking. See: TrackReleased().
+func (r *RefMap) Released() []Target {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	list := []Target{}
+	for target := range r.released {
+		list = append(list, target)
+	}
+
+	return list
+}
+
+//
+// Mark the map as synced.
+// The map is synced when the mappings of all owners have been
+// added. Called by Rebuild(); otherwise, should be called after
+// the owner informer (cache) has synced.
+func (r *RefMap) SetSynced() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.synced = true
+}
+
+//
+// Get whether the map is synced. See: SetSynced().
+func (r *RefMap) Synced() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.synced
+}
+
+//
+// A released target has been handled.
+func (r *RefMap) handled(target Target) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.released, target)
+}
+
+//
+// Impl the handler interface.
+// Enqueue the owners mapped to the target object.
+func (r *RefMap) GetRequests(a handler.MapObject) []reconcile.Request {
+	return r.requests(r.findOwners(r.targetOf(a)))
+}
+
+//
+// Build the target for an object.
+func (r *RefMap) targetOf(a handler.MapObject) Target {
+	gvk := r.resolver().ObjectKind(a.Object)
+	return Target{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Name:       a.Meta.GetName(),
+		Namespace:  a.Meta.GetNamespace(),
+	}
+}
+
+//
+// Find the accepted owners mapped to the target including
+// owners mapped to the short form (kind only) of the target.
+func (r *RefMap) findOwners(target Target) []Owner {
+	owners := r.Find(target)
+	if target.APIVersion != "" {
+		// Short form (kind only) fallback.
+		target.APIVersion = ""
+		owners = append(owners, r.Find(target)...)
+	}
+	list := []Owner{}
+	for _, owner := range owners {
+		if r.Accepted(owner) {
+			list = append(list, owner)
+		}
+	}
+
+	return list
+}
+
+//
+// Build the (unique) requests for owners.
+func (r *RefMap) requests(owners []Owner) []reconcile.Request {
+	list := []reconcile.Request{}
+	requested := map[types.NamespacedName]bool{}
+	for _, owner := range owners {
+		request := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Namespace: owner.Namespace,
+				Name:      owner.Name,
+			},
+		}
+		if !requested[request.NamespacedName] {
+			requested[request.NamespacedName] = true
+			list = append(list, request)
+		}
+	}
+
+	return list
+}
+
+//
+// Determine if the owner is accepted by the owner kind filter.
+// Owners without an APIVersion (kind only) are matched on kind.
+func (r *RefMap) Accepted(owner Owner) bool {
+	if r.OwnerKind.Empty() {
+		return true
+	}
+	if owner.Kind != r.OwnerKind.Kind {
+		return false
+	}
+	if owner.APIVersion == "" {
+		return true
+	}
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil {
+		return false
+	}
+
+	return gv.Group == r.OwnerKind.Group
+}
+
+//
+// The kind resolver.
+func (r *RefMap) resolver() *Resolver {
+	if r.Resolver != nil {
+		return r.Resolver
+	}
+
+	return defaultResolver
+}
+
+//
+// Reset (clear) all mappings.
+func (r *RefMap) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Content = map[Target]map[Owner]bool{}
+	r.index()
+}
+
 //
 // Prune empty mappings.
 func (r *RefMap) Prune() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	for key, owners := range r.Content {
 		if len(owners) == 0 {
 			delete(r.Content, key)
 		}
 	}
 }
+
+//
+// Add a (ref) mapping (edge).
+// Caller must hold the lock.
+func (r *RefMap) add(owner Owner, target Target) {
+	r.link(r.owners, owner, target)
+}
+
+//
+// Delete a (ref) mapping (edge).
+// Caller must hold the lock.
+func (r *RefMap) delete(owner Owner, target Target) {
+	r.unlink(r.owners, owner, target)
+}
+
+//
+// Replace the mappings (edges) of an owner in the index.
+// Caller must hold the lock.
+func (r *RefMap) update(index map[Owner]map[Target]bool, owner Owner, targets []Target) {
+	wanted := map[Target]bool{}
+	for _, target := range targets {
+		wanted[target] = true
+	}
+	for target := range index[owner] {
+		if !wanted[target] {
+			r.unlink(index, owner, target)
+		}
+	}
+	for target := range wanted {
+		r.link(index, owner, target)
+	}
+}
+
+//
+// Add a mapping (edge) to the index.
+// Caller must hold the lock.
+func (r *RefMap) link(index map[Owner]map[Target]bool, owner Owner, target Target) {
+	owners, found := r.Content[target]
+	if !found {
+		owners = map[Owner]bool{}
+		r.Content[target] = owners
+	}
+	owners[owner] = true
+	delete(r.released, target)
+	targets, found := index[owner]
+	if !found {
+		targets = map[Target]bool{}
+		index[owner] = targets
+	}
+	targets[target] = true
+}
+
+//
+// Delete a mapping (edge) from the index.
+// The target => owner mapping is deleted when no longer
+// in either index. Empty mappings are pruned.
+// Caller must hold the lock.
+func (r *RefMap) unlink(index map[Owner]map[Target]bool, owner Owner, target Target) {
+	if targets, found := index[owner]; found {
+		delete(targets, target)
+		if len(targets) == 0 {
+			delete(index, owner)
+		}
+	}
+	if r.owners[owner][target] || r.dependencies[owner][target] {
+		return
+	}
+	if owners, found := r.Content[target]; found {
+		delete(owners, owner)
+		if len(owners) == 0 {
+			delete(r.Content, target)
+			r.release(target)
+		}
+	}
+}
+
+//
+// Record a released target when tracking is enabled.
+// Caller must hold the lock.
+func (r *RefMap) release(target Target) {
+	if !r.trackReleased {
+		return
+	}
+	if r.released == nil {
+		r.released = map[Target]bool{}
+	}
+	r.released[target] = true
+}
+
+//
+// Build the reverse index when needed.
+// When the `Content` has been replaced, all mappings
+// are indexed as ref mappings.
+// Caller must hold the lock.
+func (r *RefMap) index() {
+	if r.Content == nil {
+		r.Content = map[Target]map[Owner]bool{}
+	}
+	if r.owners != nil && r.indexed != nil &&
+		reflect.ValueOf(r.indexed).Pointer() == reflect.ValueOf(r.Content).Pointer() {
+		return
+	}
+	r.owners = map[Owner]map[Target]bool{}
+	r.dependencies = map[Owner]map[Target]bool{}
+	r.indexed = r.Content
+	for target, owners := range r.Content {
+		for owner := range owners {
+			targets, found := r.owners[owner]
+			if !found {
+				targets = map[Target]bool{}
+				r.owners[owner] = targets
+			}
+			targets[target] = true
+		}
+	}
+}
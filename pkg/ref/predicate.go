@@ -1,8 +1,6 @@
 package ref
 
 import (
-	"k8s.io/api/core/v1"
-	"reflect"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
@@ -10,21 +8,35 @@ const (
 	Tag = "ref"
 )
 
+// Tag options.
+const (
+	// The target is cluster-scoped.
+	ClusterOption = "cluster"
+)
+
 //
 // Predicate Event Mapper
 // All ObjectReference fields with the `ref` tag will be mapped.
+// Nested fields are supported. See: Extractor.
+// Owners and targets are keyed by GVK resolved through the `Scheme`.
+// See: Resolver for the `ref` tag syntax.
 //
 // Example (CRD):
 //     type Resource struct {
-//         ThingRef *v1.ObjectReference `json:"thingRef" ref:"Thing"`
+//         ThingRef *v1.ObjectReference `json:"thingRef" ref:"Thing.example.io"`
 //     }
 //
 // Example (usage):
+//     refMap := ref.NewMap(&Resource{})
+//     mapper := refMap.Mapper()
+//     ...
 //     func (p Predicate) Create(e event.CreateEvent) bool {
 //         ...
-//         ref.Mapper.Create(e)
+//         mapper.Create(e)
 //     }}
 //
+// See: MapPredicate and RefMap.Watch() which do this for you.
+//
 type EventMapper struct {
 	Map *RefMap
 }
@@ -32,74 +44,150 @@ type EventMapper struct {
 //
 // Create event.
 func (r *EventMapper) Create(event event.CreateEvent) {
-	refOwner := Owner{
-		Kind:      ToKind(event.Object),
-		Namespace: event.Meta.GetNamespace(),
-		Name:      event.Meta.GetName(),
-	}
-	for _, ref := range r.findRefs(event.Object) {
-		r.Map.Add(refOwner, ref)
-	}
+	refOwner := r.owner(event.Object, event.Meta.GetNamespace(), event.Meta.GetName())
+	r.Map.Update(refOwner, r.findRefs(event.Object)...)
 }
 
 //
 // Update event.
+// Only changed mappings are updated.
 func (r *EventMapper) Update(event event.UpdateEvent) {
-	r.Map.DeleteOwner(Owner{
-		Kind:      ToKind(event.ObjectOld),
-		Namespace: event.MetaOld.GetNamespace(),
-		Name:      event.MetaOld.GetName(),
-	})
-	refOwner := Owner{
-		Kind:      ToKind(event.ObjectNew),
-		Namespace: event.MetaNew.GetNamespace(),
-		Name:      event.MetaNew.GetName(),
-	}
-	for _, ref := range r.findRefs(event.ObjectNew) {
-		r.Map.Add(refOwner, ref)
+	oldOwner := r.owner(event.ObjectOld, event.MetaOld.GetNamespace(), event.MetaOld.GetName())
+	refOwner := r.owner(event.ObjectNew, event.MetaNew.GetNamespace(), event.MetaNew.GetName())
+	if oldOwner != refOwner {
+		r.Map.DeleteOwner(oldOwner)
 	}
+	r.Map.Update(refOwner, r.findRefs(event.ObjectNew)...)
+}
+
+//
+// Generic event.
+// The operation is unknown so the owner mappings
+// are updated (replaced) as with Create.
+func (r *EventMapper) Generic(event event.GenericEvent) {
+	refOwner := r.owner(event.Object, event.Meta.GetNamespace(), event.Meta.GetName())
+	r.Map.Update(refOwner, r.findRefs(event.Object)...)
 }
 
 //
 // Delete Mapper.
 func (r *EventMapper) Delete(event event.DeleteEvent) {
-	r.Map.DeleteOwner(Owner{
-		Kind:      ToKind(event.Object),
-		Namespace: event.Meta.GetNamespace(),
-		Name:      event.Meta.GetName(),
-	})
+	r.Map.DeleteOwner(
+		r.owner(event.Object, event.Meta.GetNamespace(), event.Meta.GetName()))
+}
+
+//
+// Build the owner.
+func (r *EventMapper) owner(object interface{}, namespace, name string) Owner {
+	gvk := r.resolver().ObjectKind(object)
+	return Owner{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+		Namespace:  namespace,
+		Name:       name,
+	}
+}
+
+//
+// The kind resolver.
+func (r *EventMapper) resolver() *Resolver {
+	if r.Map != nil {
+		return r.Map.resolver()
+	}
+
+	return defaultResolver
 }
 
 //
 // Inspect the object for references.
 func (r *EventMapper) findRefs(object interface{}) []Target {
 	list := []Target{}
-	rt := reflect.TypeOf(object)
-	rv := reflect.ValueOf(object)
-	if rt.Kind() == reflect.Ptr {
-		rt = rt.Elem()
-		rv = rv.Elem()
-	}
-	if rv.Kind() != reflect.Struct {
-		return list
-	}
-	for i := 0; i < rt.NumField(); i++ {
-		ft := rt.Field(i)
-		fv := rv.Field(i)
-		if kind, found := ft.Tag.Lookup(Tag); found {
-			ref, cast := fv.Interface().(*v1.ObjectReference)
-			if !cast || !RefSet(ref) {
-				continue
-			}
-			list = append(
-				list,
-				Target{
-					Kind:      kind,
-					Namespace: ref.Namespace,
-					Name:      ref.Name,
-				})
-		}
+	for _, ref := range r.FindRefs(object) {
+		list = append(list, ref.Target)
 	}
 
 	return list
 }
+
+//
+// Inspect the object for references.
+// See: Extractor.
+func (r *EventMapper) FindRefs(object interface{}) []Ref {
+	extractor := Extractor{Resolver: r.resolver()}
+	return extractor.Extract(object)
+}
+
+//
+// Predicate.
+// Structurally identical to the controller-runtime
+// `predicate.Predicate` so that implementations may be
+// passed to `Controller.Watch()`.
+type Predicate interface {
+	Create(event.CreateEvent) bool
+	Delete(event.DeleteEvent) bool
+	Update(event.UpdateEvent) bool
+	Generic(event.GenericEvent) bool
+}
+
+//
+// Owner predicate.
+// Updates the map on owner events.
+// All events are accepted.
+//
+// Example:
+//     err = cnt.Watch(
+//         &source.Kind{Type: &Resource{}},
+//         &handler.EnqueueRequestForObject{},
+//         refMap.Predicate())
+//
+type MapPredicate struct {
+	Mapper *EventMapper
+}
+
+//
+// Create event.
+func (r *MapPredicate) Create(event event.CreateEvent) bool {
+	r.Mapper.Create(event)
+	return true
+}
+
+//
+// Update event.
+func (r *MapPredicate) Update(event event.UpdateEvent) bool {
+	r.Mapper.Update(event)
+	return true
+}
+
+//
+// Delete event.
+func (r *MapPredicate) Delete(event event.DeleteEvent) bool {
+	r.Mapper.Delete(event)
+	return true
+}
+
+//
+// Generic event.
+func (r *MapPredicate) Generic(event event.GenericEvent) bool {
+	r.Mapper.Generic(event)
+	return true
+}
+
+//
+// Accept all events.
+type passThrough struct{}
+
+func (r *passThrough) Create(event.CreateEvent) bool {
+	return true
+}
+
+func (r *passThrough) Update(event.UpdateEvent) bool {
+	return true
+}
+
+func (r *passThrough) Delete(event.DeleteEvent) bool {
+	return true
+}
+
+func (r *passThrough) Generic(event.GenericEvent) bool {
+	return true
+}
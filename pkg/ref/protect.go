@@ -0,0 +1,257 @@
+package ref
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jortel/controller/pkg/condition"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sort"
+)
+
+// Finalizers
+const (
+	ProtectionFinalizer = "ref.jortel.github.io/protection"
+)
+
+// Reasons
+const (
+	Referenced = "Referenced"
+)
+
+//
+// Reference protection.
+// Protects targets from deletion while referenced by owners
+// in the `Map`. A finalizer is added to targets mapped to at
+// least one owner and removed when released by the last owner.
+// When deletion of a target is blocked, the `DeletionBlocked`
+// condition is set on the target when it has conditions
+// (status.conditions).
+// Each controller (map) should use a unique `Finalizer`.
+// The map must track released targets (see: RefMap.TrackReleased)
+// and finalizers are not removed until the map is synced
+// (see: RefMap.SetSynced) so that targets of owners not yet
+// mapped remain protected.
+//
+// Example:
+//     refMap := ref.NewMap(&Thing{})
+//     refMap.TrackReleased()
+//     protector := ref.Protector{
+//         Map:       refMap,
+//         Client:    r.Client,
+//         Finalizer: "thing.example.io/protection",
+//     }
+//     ...
+//     // Reconcile (owner)
+//     err = protector.ProtectTargets(ctx, thing)
+//     ...
+//     // Reconcile (target)
+//     err = protector.Protect(ctx, secret)
+//
+type Protector struct {
+	// The ref map.
+	Map *RefMap
+	// A client.
+	Client client.Client
+	// The finalizer.
+	// Default: ProtectionFinalizer.
+	Finalizer string
+}
+
+//
+// The finalizer of a target cannot be removed
+// until the map is synced.
+type NotSyncedError struct {
+	Target Target
+}
+
+//
+// Error description.
+func (e NotSyncedError) Error() string {
+	return fmt.Sprintf(
+		"ref: map not synced, finalizer not removed: %s/%s/%s",
+		e.Target.Kind,
+		e.Target.Namespace,
+		e.Target.Name)
+}
+
+//
+// Protect the target object.
+// The finalizer is added when the target is mapped to
+// owners and removed when not mapped. Returns NotSyncedError
+// when the finalizer would be removed before the map is synced.
+func (r *Protector) Protect(ctx context.Context, object runtime.Object) error {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return err
+	}
+	target := r.Map.targetOf(handler.MapObject{Meta: accessor, Object: object})
+	owners := r.Map.findOwners(target)
+	finalizers := []string{}
+	found := false
+	for _, f := range accessor.GetFinalizers() {
+		if f == r.finalizer() {
+			found = true
+			continue
+		}
+		finalizers = append(finalizers, f)
+	}
+	deleting := accessor.GetDeletionTimestamp() != nil
+	switch {
+	case len(owners) == 0 && found:
+		if !r.Map.Synced() {
+			return NotSyncedError{Target: target}
+		}
+		accessor.SetFinalizers(finalizers)
+		return r.Client.Update(ctx, object)
+	case len(owners) > 0 && !found && !deleting:
+		accessor.SetFinalizers(append(finalizers, r.finalizer()))
+		return r.Client.Update(ctx, object)
+	case len(owners) > 0 && found && deleting:
+		conditions := r.conditions(object)
+		if conditions == nil || !r.blocked(conditions, owners) {
+			return nil
+		}
+		return r.Client.Status().Update(ctx, object)
+	}
+
+	return nil
+}
+
+//
+// Protect the targets of the owner and the
+// targets released by the last owner.
+func (r *Protector) ProtectTargets(ctx context.Context, owner runtime.Object) error {
+	accessor, err := meta.Accessor(owner)
+	if err != nil {
+		return err
+	}
+	targets := r.Map.FindTargets(r.Map.OwnerOf(accessor))
+	return r.protect(ctx, append(targets, r.Map.Released()...))
+}
+
+//
+// Protect (release) the targets released by the last owner.
+// Should be called after owners are deleted and retried on error.
+func (r *Protector) Release(ctx context.Context) error {
+	return r.protect(ctx, r.Map.Released())
+}
+
+//
+// Protect targets.
+// Targets not found or of unknown kinds are ignored.
+// Released targets are no longer tracked once handled; on
+// error, those not handled remain tracked to be retried.
+func (r *Protector) protect(ctx context.Context, targets []Target) error {
+	resolver := r.Map.resolver()
+	for _, target := range targets {
+		gvk := resolver.RefKind(target.APIVersion, target.Kind)
+		object, err := resolver.scheme().New(gvk)
+		if err != nil {
+			r.Map.handled(target)
+			continue
+		}
+		key := client.ObjectKey{
+			Namespace: target.Namespace,
+			Name:      target.Name,
+		}
+		err = r.Client.Get(ctx, key, object)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				r.Map.handled(target)
+				continue
+			}
+			return err
+		}
+		err = r.Protect(ctx, object)
+		if err != nil {
+			return err
+		}
+		r.Map.handled(target)
+	}
+
+	return nil
+}
+
+//
+// Set the `DeletionBlocked` condition.
+// Staged using the finalizer as the (condition) owner so the
+// items are expanded into the message.
+// Returns true when the conditions have changed.
+func (r *Protector) blocked(conditions *condition.Conditions, owners []Owner) bool {
+	before, _ := json.Marshal(conditions)
+	items := []string{}
+	for _, owner := range owners {
+		item := fmt.Sprintf("%s/%s", owner.Kind, owner.Name)
+		if owner.Namespace != "" {
+			item = fmt.Sprintf("%s/%s/%s", owner.Kind, owner.Namespace, owner.Name)
+		}
+		items = append(items, item)
+	}
+	sort.Strings(items)
+	conditions.BeginStagingConditions(r.finalizer())
+	conditions.SetCondition(condition.Condition{
+		Type:     condition.DeletionBlocked,
+		Status:   condition.True,
+		Reason:   Referenced,
+		Category: condition.Warn,
+		Message:  fmt.Sprintf("Deletion blocked by %d owners [].", len(owners)),
+		Items:    items,
+	})
+	conditions.EndStagingConditions()
+	after, _ := json.Marshal(conditions)
+
+	return !bytes.Equal(before, after)
+}
+
+//
+// Find the object conditions (status.conditions).
+// Returns nil when not found.
+func (r *Protector) conditions(object runtime.Object) *condition.Conditions {
+	rv := reflect.ValueOf(object)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	status := rv.FieldByName("Status")
+	if status.Kind() == reflect.Ptr {
+		if status.IsNil() {
+			return nil
+		}
+		status = status.Elem()
+	}
+	if status.Kind() != reflect.Struct {
+		return nil
+	}
+	field := status.FieldByName("Conditions")
+	if !field.IsValid() || !field.CanAddr() {
+		return nil
+	}
+	conditions, cast := field.Addr().Interface().(*condition.Conditions)
+	if !cast {
+		return nil
+	}
+
+	return conditions
+}
+
+//
+// The finalizer.
+func (r *Protector) finalizer() string {
+	if r.Finalizer != "" {
+		return r.Finalizer
+	}
+
+	return ProtectionFinalizer
+}
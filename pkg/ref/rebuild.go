@@ -0,0 +1,163 @@
+package ref
+
+import (
+	"context"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"time"
+)
+
+//
+// A mapping (edge) of a ref-owner to a ref-target.
+type Mapping struct {
+	Owner  Owner
+	Target Target
+}
+
+//
+// Drift corrected by a rebuild.
+type Drift struct {
+	// Missing mappings that were added.
+	Added []Mapping
+	// Stale mappings that were deleted.
+	Deleted []Mapping
+}
+
+//
+// No drift corrected.
+func (r *Drift) Empty() bool {
+	return len(r.Added) == 0 && len(r.Deleted) == 0
+}
+
+//
+// Rebuild the mappings of owners listed from the cache.
+// Every owner (of the kind) in the `list` is listed and inspected
+// for references. The mappings of owners of the kind are replaced
+// atomically. Mappings of other kinds are not affected.
+// Dependency mappings (see: Waiter) of listed owners are not
+// affected; those of owners no longer listed are deleted.
+// The map is marked as synced. See: SetSynced().
+// Returns the drift that was corrected.
+//
+// Example:
+//     drift, err := refMap.Rebuild(ctx, mgr.GetCache(), &ThingList{})
+func (r *RefMap) Rebuild(ctx context.Context, reader cache.Cache, list runtime.Object) (Drift, error) {
+	drift := Drift{}
+	kind, err := r.itemKind(list)
+	if err != nil {
+		return drift, err
+	}
+	err = reader.List(ctx, &client.ListOptions{}, list)
+	if err != nil {
+		return drift, err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return drift, err
+	}
+	mapper := r.Mapper()
+	wanted := map[Owner][]Target{}
+	for _, item := range items {
+		object, err := meta.Accessor(item)
+		if err != nil {
+			return drift, err
+		}
+		owner := mapper.owner(item, object.GetNamespace(), object.GetName())
+		if !r.Accepted(owner) {
+			continue
+		}
+		wanted[owner] = mapper.findRefs(item)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.index()
+	for _, index := range []map[Owner]map[Target]bool{r.owners, r.dependencies} {
+		for owner, targets := range index {
+			if owner.Kind != kind.Kind || owner.APIVersion != kind.APIVersion {
+				continue
+			}
+			if _, found := wanted[owner]; found {
+				continue
+			}
+			for target := range targets {
+				r.unlink(index, owner, target)
+				drift.Deleted = append(drift.Deleted, Mapping{Owner: owner, Target: target})
+			}
+		}
+	}
+	for owner, targets := range wanted {
+		current := r.owners[owner]
+		found := map[Target]bool{}
+		for _, target := range targets {
+			found[target] = true
+			if !current[target] {
+				r.add(owner, target)
+				drift.Added = append(drift.Added, Mapping{Owner: owner, Target: target})
+			}
+		}
+		for target := range r.owners[owner] {
+			if !found[target] {
+				r.delete(owner, target)
+				drift.Deleted = append(drift.Deleted, Mapping{Owner: owner, Target: target})
+			}
+		}
+	}
+	r.synced = true
+
+	return drift, nil
+}
+
+//
+// Periodically rebuild the map until the context is done.
+// The `report` function (optional) is called with the result
+// of each rebuild. Blocks; intended to be run as a goroutine.
+//
+// Example:
+//     go refMap.Resync(ctx, mgr.GetCache(), &ThingList{}, time.Minute,
+//         func(drift ref.Drift, err error) {
+//             ...
+//         })
+func (r *RefMap) Resync(
+	ctx context.Context,
+	reader cache.Cache,
+	list runtime.Object,
+	interval time.Duration,
+	report func(Drift, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		drift, err := r.Rebuild(ctx, reader, list.DeepCopyObject())
+		if report != nil {
+			report(drift, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+//
+// Resolve the owner (APIVersion and Kind) of the list items.
+func (r *RefMap) itemKind(list runtime.Object) (Owner, error) {
+	itemsPtr, err := meta.GetItemsPtr(list)
+	if err != nil {
+		return Owner{}, err
+	}
+	itemType := reflect.TypeOf(itemsPtr).Elem().Elem()
+	if itemType.Kind() == reflect.Ptr {
+		itemType = itemType.Elem()
+	}
+	gvk := r.resolver().ObjectKind(reflect.New(itemType).Interface())
+	kind := Owner{
+		APIVersion: gvk.GroupVersion().String(),
+		Kind:       gvk.Kind,
+	}
+
+	return kind, nil
+}
@@ -2,22 +2,31 @@ package ref
 
 import (
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
 	"reflect"
 )
 
 // Global
+// Deprecated: Map and Mapper are shared by all controllers.
+// Construct a controller specific map using NewMap().
 var Map *RefMap
 var Mapper *EventMapper
+var Scheme *runtime.Scheme
+var RESTMapper meta.RESTMapper
+
+// The default kind resolver.
+// Uses the `Scheme` and `RESTMapper`.
+var defaultResolver *Resolver
 
 //
 // Build globals.
 func init() {
-	Map = &RefMap{
-		Content: map[Target]map[Owner]bool{},
-	}
-	Mapper = &EventMapper{
-		Map: Map,
-	}
+	Scheme = scheme.Scheme
+	defaultResolver = &Resolver{}
+	Map = NewMap(nil)
+	Mapper = Map.Mapper()
 }
 
 //
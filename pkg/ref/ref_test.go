@@ -1,14 +1,27 @@
 package ref
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jortel/controller/pkg/condition"
 	"github.com/onsi/gomega"
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"testing"
+	"time"
 )
 
 type _Thing struct {
@@ -262,7 +275,7 @@ func TestHandler(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 
 	// Setup
-	Map.Content = map[Target]map[Owner]bool{}
+	Map.Reset()
 	owner := &_Thing{
 		ObjectMeta: meta.ObjectMeta{
 			Namespace: "ns0",
@@ -311,3 +324,1203 @@ func TestHandler(t *testing.T) {
 
 	g.Expect(len(list)).To(gomega.Equal(1))
 }
+
+func TestWaitFor(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	m := &RefMap{
+		Content: map[Target]map[Owner]bool{},
+	}
+	conditions := condition.Conditions{}
+	thing := &_Thing{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "joe",
+		},
+	}
+	waiter := NewWaiter(m, thing, &conditions)
+	owner := waiter.Owner
+	refA := &v1.ObjectReference{
+		Name: "s1",
+	}
+	refB := &v1.ObjectReference{
+		Kind:      "ThingB",
+		Namespace: "nsB",
+		Name:      "thingB",
+	}
+	secret := &v1.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "s1",
+		},
+	}
+	targetA := Target{APIVersion: "v1", Kind: "Secret", Namespace: "ns0", Name: "s1"}
+	targetB := Target{Kind: "ThingB", Namespace: "nsB", Name: "thingB"}
+	ready := &condition.Conditions{}
+	ready.SetReady(true, "Ready.")
+
+	// Test not found and not ready.
+	conditions.BeginStagingConditions()
+	g.Expect(waiter.WaitFor(refA, &v1.Secret{}, nil)).To(gomega.BeFalse())
+	g.Expect(waiter.WaitFor(refB, nil, &condition.Conditions{})).To(gomega.BeFalse())
+	waiter.SetCondition()
+	conditions.EndStagingConditions()
+
+	// Validation
+	found := conditions.FindCondition(condition.DependencyNotReady)
+	g.Expect(found).NotTo(gomega.BeNil())
+	g.Expect(found.Message).To(gomega.Equal(
+		"Waiting for dependencies [Secret/ns0/s1,ThingB/nsB/thingB] to be ready."))
+	g.Expect(owner).To(gomega.Equal(Owner{Kind: ToKind(thing), Namespace: "ns0", Name: "joe"}))
+	g.Expect(m.Match(targetA, owner)).To(gomega.BeTrue())
+	g.Expect(m.Match(targetB, owner)).To(gomega.BeTrue())
+	g.Expect(m.GetRequests(handler.MapObject{Meta: secret, Object: secret})).To(gomega.Equal(
+		[]reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: "ns0", Name: "joe"}}}))
+
+	// Test unchanged (not a transition).
+	transitioned := past()
+	found.LastTransitionTime = transitioned
+	conditions.BeginStagingConditions()
+	g.Expect(waiter.WaitFor(refA, secret, nil)).To(gomega.BeFalse())
+	g.Expect(waiter.WaitFor(refB, nil, nil)).To(gomega.BeFalse())
+	waiter.SetCondition()
+	conditions.EndStagingConditions()
+
+	// Validation
+	found = conditions.FindCondition(condition.DependencyNotReady)
+	g.Expect(found.LastTransitionTime).To(gomega.Equal(transitioned))
+
+	// Test one ready.
+	conditions.BeginStagingConditions()
+	g.Expect(waiter.WaitFor(refA, secret, ready)).To(gomega.BeTrue())
+	g.Expect(waiter.WaitFor(refB, nil, nil)).To(gomega.BeFalse())
+	waiter.SetCondition()
+	conditions.EndStagingConditions()
+
+	// Validation
+	found = conditions.FindCondition(condition.DependencyNotReady)
+	g.Expect(found.Message).To(gomega.Equal(
+		"Waiting for dependencies [ThingB/nsB/thingB] to be ready."))
+	g.Expect(m.FindTargets(owner)).To(gomega.Equal([]Target{targetB}))
+
+	// Test dependencies kept on update.
+	m.Mapper().Update(event.UpdateEvent{
+		MetaOld:   thing,
+		ObjectOld: thing,
+		MetaNew:   thing,
+		ObjectNew: thing,
+	})
+
+	// Validation
+	g.Expect(m.FindTargets(owner)).To(gomega.Equal([]Target{targetB}))
+
+	// Test all ready (not staging).
+	g.Expect(waiter.WaitFor(refA, secret, ready)).To(gomega.BeTrue())
+	g.Expect(waiter.WaitFor(refB, nil, ready)).To(gomega.BeTrue())
+	waiter.SetCondition()
+
+	// Validation
+	g.Expect(conditions.FindCondition(condition.DependencyNotReady)).To(gomega.BeNil())
+	g.Expect(m.FindTargets(owner)).To(gomega.BeEmpty())
+	g.Expect(m.Content).To(gomega.BeEmpty())
+
+	// Test owner deleted.
+	conditions.BeginStagingConditions()
+	g.Expect(waiter.WaitFor(refB, nil, nil)).To(gomega.BeFalse())
+	waiter.SetCondition()
+	conditions.EndStagingConditions()
+	m.Mapper().Delete(event.DeleteEvent{Meta: thing, Object: thing})
+
+	// Validation
+	g.Expect(m.FindTargets(owner)).To(gomega.BeEmpty())
+	g.Expect(m.Content).To(gomega.BeEmpty())
+}
+
+//
+// A time in the past.
+func past() meta.Time {
+	return meta.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+}
+
+type Secret struct {
+	_Thing
+}
+
+type _Owner struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+	SecretRef       *v1.ObjectReference `json:"secretRef" ref:"Secret"`
+	ThingSecretRef  *v1.ObjectReference `json:"thingSecretRef" ref:"Secret.example.io"`
+}
+
+func (t *_Owner) GetObjectKind() schema.ObjectKind {
+	return nil
+}
+
+func (t *_Owner) DeepCopyObject() runtime.Object {
+	return t
+}
+
+func TestResolver(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	scheme := runtime.NewScheme()
+	_ = v1.AddToScheme(scheme)
+	scheme.AddKnownTypes(schema.GroupVersion{Group: "example.io", Version: "v1"}, &Secret{})
+	resolver := Resolver{Scheme: scheme}
+
+	// Test
+	g.Expect(resolver.TagKind("Secret")).To(gomega.Equal(
+		schema.GroupVersionKind{Version: "v1", Kind: "Secret"}))
+	g.Expect(resolver.TagKind("Secret.example.io")).To(gomega.Equal(
+		schema.GroupVersionKind{Group: "example.io", Version: "v1", Kind: "Secret"}))
+	g.Expect(resolver.TagKind("Thing")).To(gomega.Equal(
+		schema.GroupVersionKind{Kind: "Thing"}))
+	g.Expect(resolver.ObjectKind(&v1.Secret{})).To(gomega.Equal(
+		schema.GroupVersionKind{Version: "v1", Kind: "Secret"}))
+	g.Expect(resolver.ObjectKind(&Secret{})).To(gomega.Equal(
+		schema.GroupVersionKind{Group: "example.io", Version: "v1", Kind: "Secret"}))
+	g.Expect(resolver.ObjectKind(&_Thing{})).To(gomega.Equal(
+		schema.GroupVersionKind{Kind: "_Thing"}))
+}
+
+func TestHandlerGroupQualified(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	scheme := runtime.NewScheme()
+	_ = v1.AddToScheme(scheme)
+	scheme.AddKnownTypes(schema.GroupVersion{Group: "example.io", Version: "v1"}, &Secret{})
+	m := NewMap(nil, &Resolver{Scheme: scheme})
+	ownerA := &_Owner{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "ownerA",
+		},
+		SecretRef: &v1.ObjectReference{
+			Namespace: "ns1",
+			Name:      "s1",
+		},
+	}
+	ownerB := &_Owner{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "ownerB",
+		},
+		ThingSecretRef: &v1.ObjectReference{
+			Namespace: "ns1",
+			Name:      "s1",
+		},
+	}
+	mapper := m.Mapper()
+	mapper.Create(event.CreateEvent{Meta: ownerA, Object: ownerA})
+	mapper.Create(event.CreateEvent{Meta: ownerB, Object: ownerB})
+	secret := &v1.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns1",
+			Name:      "s1",
+		},
+	}
+	thingSecret := &Secret{
+		_Thing: _Thing{
+			ObjectMeta: meta.ObjectMeta{
+				Namespace: "ns1",
+				Name:      "s1",
+			},
+		},
+	}
+
+	// Test
+	listA := m.GetRequests(handler.MapObject{Meta: secret, Object: secret})
+	listB := m.GetRequests(handler.MapObject{Meta: thingSecret, Object: thingSecret})
+
+	// Validation
+	g.Expect(len(listA)).To(gomega.Equal(1))
+	g.Expect(listA[0].Name).To(gomega.Equal("ownerA"))
+	g.Expect(len(listB)).To(gomega.Equal(1))
+	g.Expect(listB[0].Name).To(gomega.Equal("ownerB"))
+}
+
+type _Nested struct {
+	Provider *v1.ObjectReference `json:"provider" ref:"Provider"`
+	Next     *_Nested            `json:"next,omitempty"`
+}
+
+type _Embedded struct {
+	Storage v1.ObjectReference `json:"storage" ref:"StorageClass"`
+}
+
+type _Spec struct {
+	_Embedded `json:",inline"`
+	Nested    _Nested                        `json:"nested"`
+	Hosts     []v1.ObjectReference           `json:"hosts" ref:"Host"`
+	Secrets   map[string]*v1.ObjectReference `json:"secrets" ref:"Secret"`
+	Networks  [2]*v1.ObjectReference         `json:"networks" ref:"Network"`
+	Ignored   []v1.ObjectReference           `json:"ignored"`
+}
+
+type _Deep struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+	Spec            _Spec `json:"spec"`
+}
+
+func TestFindRefsNested(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	ref := func(name string) v1.ObjectReference {
+		return v1.ObjectReference{Namespace: "ns1", Name: name}
+	}
+	p1, p2, s1, n1 := ref("p1"), ref("p2"), ref("s1"), ref("n1")
+	deep := &_Deep{
+		Spec: _Spec{
+			_Embedded: _Embedded{
+				Storage: ref("st1"),
+			},
+			Nested: _Nested{
+				Provider: &p1,
+				Next: &_Nested{
+					Provider: &p2,
+				},
+			},
+			Hosts: []v1.ObjectReference{
+				ref("h1"),
+				{Name: "notSet"},
+				ref("h2"),
+			},
+			Secrets: map[string]*v1.ObjectReference{
+				"b": &s1,
+				"a": nil,
+			},
+			Networks: [2]*v1.ObjectReference{nil, &n1},
+			Ignored:  []v1.ObjectReference{ref("i1")},
+		},
+	}
+	// Cycle.
+	deep.Spec.Nested.Next.Next = &deep.Spec.Nested
+
+	// Test
+	mapper := EventMapper{Map}
+	found := mapper.FindRefs(deep)
+
+	// Validation
+	paths := map[string]string{}
+	for _, ref := range found {
+		paths[ref.Path] = ref.Target.Kind + "/" + ref.Target.Name
+	}
+	g.Expect(paths).To(gomega.Equal(map[string]string{
+		"spec.storage":              "StorageClass/st1",
+		"spec.nested.provider":      "Provider/p1",
+		"spec.nested.next.provider": "Provider/p2",
+		"spec.hosts[0]":             "Host/h1",
+		"spec.hosts[2]":             "Host/h2",
+		"spec.secrets[b]":           "Secret/s1",
+		"spec.networks[1]":          "Network/n1",
+	}))
+}
+
+type _CustomRef struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+func (r _CustomRef) RefTarget() Target {
+	return Target{Namespace: r.Namespace, Name: r.Name}
+}
+
+type _Adapted struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+	Config          v1.LocalObjectReference         `json:"config" ref:"ConfigMap"`
+	Source          *v1.TypedLocalObjectReference   `json:"source" ref:""`
+	Secret          v1.SecretReference              `json:"secret" ref:"Secret"`
+	NoNsSecret      v1.SecretReference              `json:"noNsSecret" ref:"Secret"`
+	Things          []_CustomRef                    `json:"things" ref:"Thing"`
+	Untagged        v1.LocalObjectReference         `json:"untagged"`
+	Unknown         *v1.TypedLocalObjectReference   `json:"unknown"`
+	Typed           []*v1.TypedLocalObjectReference `json:"typed" ref:"Ignored"`
+}
+
+func (t *_Adapted) GetObjectKind() schema.ObjectKind {
+	return nil
+}
+
+func (t *_Adapted) DeepCopyObject() runtime.Object {
+	return t
+}
+
+func TestFindRefsAdapted(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	group := "example.io"
+	object := &_Adapted{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "joe",
+		},
+		Config: v1.LocalObjectReference{Name: "c1"},
+		Source: &v1.TypedLocalObjectReference{
+			Kind: "PersistentVolumeClaim",
+			Name: "pvc1",
+		},
+		Secret:     v1.SecretReference{Namespace: "ns1", Name: "s1"},
+		NoNsSecret: v1.SecretReference{Name: "s2"},
+		Things: []_CustomRef{
+			{Name: "t1"},
+			{Namespace: "ns2", Name: "t2"},
+		},
+		Untagged: v1.LocalObjectReference{Name: "u1"},
+		Typed: []*v1.TypedLocalObjectReference{
+			{APIGroup: &group, Kind: "Widget", Name: "w1"},
+		},
+	}
+
+	// Test
+	mapper := EventMapper{Map}
+	found := mapper.FindRefs(object)
+
+	// Validation
+	paths := map[string]Target{}
+	for _, ref := range found {
+		paths[ref.Path] = ref.Target
+	}
+	g.Expect(paths).To(gomega.Equal(map[string]Target{
+		"config":    {APIVersion: "v1", Kind: "ConfigMap", Namespace: "ns0", Name: "c1"},
+		"source":    {APIVersion: "v1", Kind: "PersistentVolumeClaim", Namespace: "ns0", Name: "pvc1"},
+		"secret":    {APIVersion: "v1", Kind: "Secret", Namespace: "ns1", Name: "s1"},
+		"things[0]": {Kind: "Thing", Namespace: "ns0", Name: "t1"},
+		"things[1]": {Kind: "Thing", Namespace: "ns2", Name: "t2"},
+		"typed[0]":  {Kind: "Widget", Namespace: "ns0", Name: "w1"},
+	}))
+}
+
+type _Clustered struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+	Storage         *v1.ObjectReference `json:"storage" ref:"StorageClass,cluster"`
+	Node            *v1.ObjectReference `json:"node" ref:"Node"`
+	Config          *v1.ObjectReference `json:"config" ref:"ConfigMap"`
+}
+
+func (t *_Clustered) GetObjectKind() schema.ObjectKind {
+	return nil
+}
+
+func (t *_Clustered) DeepCopyObject() runtime.Object {
+	return t
+}
+
+func TestClusterScoped(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	mapper := apimeta.NewDefaultRESTMapper(nil)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "Node"}, apimeta.RESTScopeRoot)
+	mapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, apimeta.RESTScopeNamespace)
+	m := NewMap(nil, &Resolver{RESTMapper: mapper})
+	owner := &_Clustered{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "joe",
+		},
+		Storage: &v1.ObjectReference{Name: "fast"},
+		Node:    &v1.ObjectReference{Namespace: "ignored", Name: "n1"},
+		Config:  &v1.ObjectReference{Name: "notSet"},
+	}
+	clusterOwner := &_Clustered{
+		ObjectMeta: meta.ObjectMeta{
+			Name: "cluster",
+		},
+		Node: &v1.ObjectReference{Name: "n1"},
+	}
+
+	// Test
+	eventMapper := m.Mapper()
+	eventMapper.Create(event.CreateEvent{Meta: owner, Object: owner})
+	eventMapper.Create(event.CreateEvent{Meta: clusterOwner, Object: clusterOwner})
+	node := &v1.Node{
+		ObjectMeta: meta.ObjectMeta{
+			Name: "n1",
+		},
+	}
+	list := m.GetRequests(handler.MapObject{Meta: node, Object: node})
+
+	// Validation
+	storage := (&Resolver{}).TagKind("StorageClass")
+	paths := map[string]Target{}
+	for _, ref := range eventMapper.FindRefs(owner) {
+		paths[ref.Path] = ref.Target
+	}
+	g.Expect(paths).To(gomega.Equal(map[string]Target{
+		"storage": {APIVersion: storage.GroupVersion().String(), Kind: "StorageClass", Name: "fast"},
+		"node":    {APIVersion: "v1", Kind: "Node", Name: "n1"},
+	}))
+	g.Expect(len(list)).To(gomega.Equal(2))
+	g.Expect(list).To(gomega.ContainElement(reconcile.Request{
+		NamespacedName: types.NamespacedName{Namespace: "ns0", Name: "joe"},
+	}))
+	g.Expect(list).To(gomega.ContainElement(reconcile.Request{
+		NamespacedName: types.NamespacedName{Name: "cluster"},
+	}))
+}
+
+func TestRefMapIndex(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	owner := Owner{Kind: "Thing", Namespace: "ns0", Name: "joe"}
+	other := Owner{Kind: "Thing", Namespace: "ns0", Name: "other"}
+	targetA := Target{Kind: "ThingA", Namespace: "ns0", Name: "a"}
+	targetB := Target{Kind: "ThingB", Namespace: "ns0", Name: "b"}
+	targetC := Target{Kind: "ThingC", Namespace: "ns0", Name: "c"}
+	m := &RefMap{
+		Content: map[Target]map[Owner]bool{
+			targetA: {owner: true, other: true},
+		},
+	}
+
+	// Test
+	found := m.FindTargets(owner)
+	m.Update(owner, targetB, targetC)
+	updated := m.FindTargets(owner)
+	m.Content = map[Target]map[Owner]bool{}
+	replaced := m.FindTargets(owner)
+	m.UpdateDependencies(owner, targetB)
+	m.Reset()
+	reset := m.FindTargets(owner)
+	m.Add(owner, targetA)
+	m.DeleteOwner(owner)
+
+	// Validation
+	g.Expect(found).To(gomega.Equal([]Target{targetA}))
+	g.Expect(updated).To(gomega.ConsistOf(targetB, targetC))
+	g.Expect(replaced).To(gomega.BeEmpty())
+	g.Expect(reset).To(gomega.BeEmpty())
+	g.Expect(m.FindTargets(owner)).To(gomega.BeEmpty())
+	g.Expect(m.Content).To(gomega.BeEmpty())
+}
+
+func TestRefMapUpdate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	owner := Owner{Kind: "Thing", Namespace: "ns0", Name: "joe"}
+	other := Owner{Kind: "Thing", Namespace: "ns0", Name: "other"}
+	targetA := Target{Kind: "ThingA", Namespace: "ns0", Name: "a"}
+	targetB := Target{Kind: "ThingB", Namespace: "ns0", Name: "b"}
+	targetC := Target{Kind: "ThingC", Namespace: "ns0", Name: "c"}
+	m := &RefMap{}
+	m.Update(owner, targetA, targetB)
+	m.Update(other, targetA)
+
+	// Test
+	m.Update(owner, targetB, targetC)
+
+	// Validation
+	g.Expect(m.Match(targetA, owner)).To(gomega.BeFalse())
+	g.Expect(m.Match(targetA, other)).To(gomega.BeTrue())
+	g.Expect(m.Match(targetB, owner)).To(gomega.BeTrue())
+	g.Expect(m.Match(targetC, owner)).To(gomega.BeTrue())
+	g.Expect(len(m.Content)).To(gomega.Equal(3))
+	g.Expect(m.Find(targetA)).To(gomega.Equal([]Owner{other}))
+}
+
+//
+// Build a map with (owners * targets) edges.
+func benchmarkMap(owners, targets int) *RefMap {
+	m := &RefMap{
+		Content: map[Target]map[Owner]bool{},
+	}
+	for i := 0; i < owners; i++ {
+		owner := Owner{Kind: "Thing", Namespace: "ns0", Name: fmt.Sprintf("owner-%d", i)}
+		for n := 0; n < targets; n++ {
+			m.Add(owner, Target{Kind: "Secret", Namespace: "ns0", Name: fmt.Sprintf("target-%d-%d", i, n)})
+		}
+	}
+
+	return m
+}
+
+//
+// Delete all owner mappings by scanning the content.
+// The approach used prior to the reverse index; for comparison.
+func deleteOwnerByScan(m *RefMap, owner Owner) {
+	for _, owners := range m.Content {
+		delete(owners, owner)
+	}
+	m.Prune()
+}
+
+func BenchmarkDeleteOwner(b *testing.B) {
+	m := benchmarkMap(10000, 10)
+	owner := Owner{Kind: "Thing", Namespace: "ns0", Name: "owner-0"}
+	targets := m.FindTargets(owner)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.DeleteOwner(owner)
+		m.Update(owner, targets...)
+	}
+}
+
+func BenchmarkDeleteOwnerByScan(b *testing.B) {
+	m := benchmarkMap(10000, 10)
+	owner := Owner{Kind: "Thing", Namespace: "ns0", Name: "owner-0"}
+	targets := m.FindTargets(owner)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		deleteOwnerByScan(m, owner)
+		m.Update(owner, targets...)
+	}
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	m := benchmarkMap(10000, 10)
+	owner := Owner{Kind: "Thing", Namespace: "ns0", Name: "owner-0"}
+	targets := m.FindTargets(owner)
+	changed := append([]Target{}, targets[1:]...)
+	changed = append(changed, Target{Kind: "Secret", Namespace: "ns0", Name: "changed"})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if i%2 == 0 {
+			m.Update(owner, changed...)
+		} else {
+			m.Update(owner, targets...)
+		}
+	}
+}
+
+func BenchmarkFindTargets(b *testing.B) {
+	m := benchmarkMap(10000, 10)
+	owner := Owner{Kind: "Thing", Namespace: "ns0", Name: "owner-0"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.FindTargets(owner)
+	}
+}
+
+func TestNewMap(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	mapA := NewMap(&_Owner{})
+	mapB := NewMap(&_Adapted{})
+	owner := &_Owner{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "owner",
+		},
+		SecretRef: &v1.ObjectReference{
+			Namespace: "ns0",
+			Name:      "s1",
+		},
+	}
+	adapted := &_Adapted{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "adapted",
+		},
+		Secret: v1.SecretReference{
+			Namespace: "ns0",
+			Name:      "s1",
+		},
+	}
+	for _, m := range []*RefMap{mapA, mapB} {
+		mapper := m.Mapper()
+		mapper.Create(event.CreateEvent{Meta: owner, Object: owner})
+		mapper.Create(event.CreateEvent{Meta: adapted, Object: adapted})
+	}
+	secret := &v1.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "s1",
+		},
+	}
+
+	// Test
+	listA := mapA.GetRequests(handler.MapObject{Meta: secret, Object: secret})
+	listB := mapB.GetRequests(handler.MapObject{Meta: secret, Object: secret})
+
+	// Validation
+	g.Expect(mapA.OwnerKind.Kind).To(gomega.Equal(ToKind(owner)))
+	g.Expect(listA).To(gomega.Equal([]reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "ns0", Name: "owner"}},
+	}))
+	g.Expect(listB).To(gomega.Equal([]reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "ns0", Name: "adapted"}},
+	}))
+	g.Expect(mapA.Accepted(Owner{Kind: ToKind(owner)})).To(gomega.BeTrue())
+	g.Expect(mapA.Accepted(Owner{Kind: ToKind(adapted)})).To(gomega.BeFalse())
+	g.Expect(NewMap(nil).Accepted(Owner{Kind: ToKind(adapted)})).To(gomega.BeTrue())
+}
+
+type _OwnerList struct {
+	meta.TypeMeta `json:",inline"`
+	meta.ListMeta `json:"metadata,omitempty"`
+	Items         []_Owner `json:"items"`
+}
+
+func (t *_OwnerList) GetObjectKind() schema.ObjectKind {
+	return nil
+}
+
+func (t *_OwnerList) DeepCopyObject() runtime.Object {
+	return t
+}
+
+//
+// Fake cache.
+// Only List() is supported.
+type _Cache struct {
+	cache.Cache
+	owners []_Owner
+}
+
+func (c *_Cache) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	list.(*_OwnerList).Items = c.owners
+	return nil
+}
+
+func TestRebuild(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	m := NewMap(nil)
+	ownerA := _Owner{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "ownerA",
+		},
+		SecretRef: &v1.ObjectReference{
+			Namespace: "ns0",
+			Name:      "s1",
+		},
+	}
+	ownerB := _Owner{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "ownerB",
+		},
+		SecretRef: &v1.ObjectReference{
+			Namespace: "ns0",
+			Name:      "s2",
+		},
+	}
+	mapper := m.Mapper()
+	mapper.Create(event.CreateEvent{Meta: &ownerA, Object: &ownerA})
+	secret := Target{APIVersion: "v1", Kind: "Secret", Namespace: "ns0"}
+	stale := Owner{Kind: ToKind(&ownerA), Namespace: "ns0", Name: "deleted"}
+	other := Owner{Kind: "Other", Namespace: "ns0", Name: "other"}
+	s1 := secret
+	s1.Name = "s1"
+	s2 := secret
+	s2.Name = "s2"
+	s3 := secret
+	s3.Name = "s3"
+	m.Add(stale, s1)
+	m.Add(other, s3)
+	ownerA.SecretRef.Name = "s3"
+	reader := &_Cache{owners: []_Owner{ownerA, ownerB}}
+	a := Owner{Kind: ToKind(&ownerA), Namespace: "ns0", Name: "ownerA"}
+	b := Owner{Kind: ToKind(&ownerB), Namespace: "ns0", Name: "ownerB"}
+
+	synced := m.Synced()
+
+	// Test
+	drift, err := m.Rebuild(context.TODO(), reader, &_OwnerList{})
+	again, err2 := m.Rebuild(context.TODO(), reader, &_OwnerList{})
+
+	// Validation
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(err2).To(gomega.BeNil())
+	g.Expect(synced).To(gomega.BeFalse())
+	g.Expect(m.Synced()).To(gomega.BeTrue())
+	g.Expect(drift.Added).To(gomega.ConsistOf(
+		Mapping{Owner: a, Target: s3},
+		Mapping{Owner: b, Target: s2}))
+	g.Expect(drift.Deleted).To(gomega.ConsistOf(
+		Mapping{Owner: a, Target: s1},
+		Mapping{Owner: stale, Target: s1}))
+	g.Expect(again.Empty()).To(gomega.BeTrue())
+	g.Expect(m.FindTargets(a)).To(gomega.Equal([]Target{s3}))
+	g.Expect(m.FindTargets(b)).To(gomega.Equal([]Target{s2}))
+	g.Expect(m.FindTargets(stale)).To(gomega.BeEmpty())
+	g.Expect(m.FindTargets(other)).To(gomega.Equal([]Target{s3}))
+}
+
+func TestWatch(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	m := &RefMap{}
+	watched := map[string]Predicate{}
+	handlers := map[string]handler.EventHandler{}
+	watch := func(kind runtime.Object, h handler.EventHandler, p Predicate) error {
+		watched[ToKind(kind)] = p
+		handlers[ToKind(kind)] = h
+		return nil
+	}
+	owner := &_Owner{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "owner",
+		},
+		SecretRef: &v1.ObjectReference{
+			Namespace: "ns0",
+			Name:      "s1",
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "s1",
+		},
+	}
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+
+	// Test
+	err := m.Watch(watch, owner, secret)
+	accepted := watched[ToKind(owner)].Create(event.CreateEvent{Meta: owner, Object: owner})
+	handlers[ToKind(secret)].Delete(event.DeleteEvent{Meta: secret, Object: secret}, queue)
+
+	// Validation
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(accepted).To(gomega.BeTrue())
+	g.Expect(m.OwnerKind.Kind).To(gomega.Equal(ToKind(owner)))
+	g.Expect(handlers[ToKind(owner)]).To(gomega.Equal(&handler.EnqueueRequestForObject{}))
+	g.Expect(watched[ToKind(secret)].Delete(event.DeleteEvent{})).To(gomega.BeTrue())
+	g.Expect(queue.Len()).To(gomega.Equal(1))
+	item, _ := queue.Get()
+	g.Expect(item).To(gomega.Equal(reconcile.Request{
+		NamespacedName: types.NamespacedName{Namespace: "ns0", Name: "owner"},
+	}))
+}
+
+func TestMapperGeneric(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	m := NewMap(nil)
+	owner := &_Owner{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "owner",
+		},
+		SecretRef: &v1.ObjectReference{
+			Namespace: "ns0",
+			Name:      "s1",
+		},
+	}
+
+	// Test
+	accepted := m.Predicate().Generic(event.GenericEvent{Meta: owner, Object: owner})
+
+	// Validation
+	g.Expect(accepted).To(gomega.BeTrue())
+	g.Expect(m.FindTargets(Owner{Kind: ToKind(owner), Namespace: "ns0", Name: "owner"})).To(
+		gomega.Equal([]Target{{APIVersion: "v1", Kind: "Secret", Namespace: "ns0", Name: "s1"}}))
+}
+
+func TestTargetGone(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	m := NewMap(&_Owner{})
+	m.TrackGone()
+	untracked := NewMap(&_Owner{})
+	owner := &_Owner{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "owner",
+		},
+		SecretRef: &v1.ObjectReference{
+			Namespace: "ns0",
+			Name:      "s1",
+		},
+	}
+	m.Mapper().Create(event.CreateEvent{Meta: owner, Object: owner})
+	untracked.Mapper().Create(event.CreateEvent{Meta: owner, Object: owner})
+	secret := &v1.Secret{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "s1",
+		},
+	}
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{Namespace: "ns0", Name: "owner"},
+	}
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	h := m.Handler()
+
+	// Test
+	h.Delete(event.DeleteEvent{Meta: secret, Object: secret}, queue)
+	gone := m.Gone(request)
+	cleared := m.Gone(request)
+	h.Delete(event.DeleteEvent{Meta: secret, Object: secret}, queue)
+	h.Create(event.CreateEvent{Meta: secret, Object: secret}, queue)
+	restored := m.Gone(request)
+	untracked.Handler().Delete(event.DeleteEvent{Meta: secret, Object: secret}, queue)
+	ignored := untracked.Gone(request)
+
+	// Validation
+	g.Expect(queue.Len()).To(gomega.Equal(1))
+	item, _ := queue.Get()
+	g.Expect(item).To(gomega.Equal(request))
+	g.Expect(gone).To(gomega.Equal([]Target{
+		{APIVersion: "v1", Kind: "Secret", Namespace: "ns0", Name: "s1"},
+	}))
+	g.Expect(cleared).To(gomega.BeEmpty())
+	g.Expect(restored).To(gomega.BeEmpty())
+	g.Expect(ignored).To(gomega.BeEmpty())
+	g.Expect(untracked.gone).To(gomega.BeEmpty())
+	g.Expect(m.Match(Target{APIVersion: "v1", Kind: "Secret", Namespace: "ns0", Name: "s1"},
+		Owner{Kind: ToKind(owner), Namespace: "ns0", Name: "owner"})).To(gomega.BeTrue())
+}
+
+type _Validated struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+	Secret          *v1.ObjectReference `json:"secret" ref:"Secret"`
+	Missing         *v1.ObjectReference `json:"missing" ref:"Secret"`
+	Denied          *v1.ObjectReference `json:"denied" ref:"ConfigMap"`
+	Wrong           *v1.ObjectReference `json:"wrong" ref:"ConfigMap"`
+	Unknown         *v1.ObjectReference `json:"unknown" ref:"Unknown"`
+}
+
+//
+// Fake client reader.
+// Get() returns the error mapped to the name.
+type _Reader struct {
+	errors map[string]error
+}
+
+func (r *_Reader) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	return r.errors[key.Name]
+}
+
+func (r *_Reader) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	return nil
+}
+
+func TestValidator(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	gr := schema.GroupResource{Resource: "secrets"}
+	reader := &_Reader{
+		errors: map[string]error{
+			"missing": errors.NewNotFound(gr, "missing"),
+			"denied":  errors.NewForbidden(gr, "denied", fmt.Errorf("denied")),
+		},
+	}
+	owner := &_Validated{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "owner",
+		},
+		Secret:  &v1.ObjectReference{Namespace: "ns0", Name: "found"},
+		Missing: &v1.ObjectReference{Namespace: "ns1", Name: "missing"},
+		Denied:  &v1.ObjectReference{Namespace: "ns0", Name: "denied"},
+		Wrong:   &v1.ObjectReference{Kind: "Secret", Namespace: "ns0", Name: "wrong"},
+		Unknown: &v1.ObjectReference{Namespace: "ns0", Name: "unknown"},
+	}
+	conditions := condition.Conditions{}
+	validator := Validator{Client: reader}
+
+	// Test
+	validation, err := validator.Validate(context.TODO(), owner)
+	validation.SetCondition(&conditions)
+
+	// Validation
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(validation.Valid()).To(gomega.BeFalse())
+	g.Expect(len(validation.Missing)).To(gomega.Equal(1))
+	g.Expect(validation.Missing[0].Path).To(gomega.Equal("missing"))
+	g.Expect(len(validation.Forbidden)).To(gomega.Equal(1))
+	g.Expect(validation.Forbidden[0].Path).To(gomega.Equal("denied"))
+	g.Expect(len(validation.WrongKind)).To(gomega.Equal(2))
+	g.Expect(validation.WrongKind[0].Path).To(gomega.Equal("wrong"))
+	g.Expect(validation.WrongKind[1].Path).To(gomega.Equal("unknown"))
+	found := conditions.FindCondition(condition.InvalidRef)
+	g.Expect(found).NotTo(gomega.BeNil())
+	g.Expect(found.Category).To(gomega.Equal(condition.Error))
+	g.Expect(found.Items).To(gomega.Equal([]string{
+		"denied: Forbidden ConfigMap/ns0/denied",
+		"missing: NotFound Secret/ns1/missing",
+		"unknown: WrongKind Unknown/ns0/unknown",
+		"wrong: WrongKind Secret/ns0/wrong",
+	}))
+
+	// Test
+	owner.Missing = nil
+	owner.Denied = nil
+	owner.Wrong = nil
+	owner.Unknown = nil
+	validation, err = validator.Validate(context.TODO(), owner)
+	validation.SetCondition(&conditions)
+
+	// Validation
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(validation.Valid()).To(gomega.BeTrue())
+	g.Expect(conditions.FindCondition(condition.InvalidRef)).To(gomega.BeNil())
+}
+
+func TestValidationItems(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	validation := Validation{
+		Missing: []Ref{
+			{
+				Path:   "spec.hosts[0]",
+				Target: Target{Kind: "Host", Namespace: "ns1", Name: "h1"},
+			},
+			{
+				Path:   "spec.tls[a,b]",
+				Target: Target{Kind: "Secret", Namespace: "ns1", Name: "s1"},
+			},
+		},
+	}
+	conditions := condition.Conditions{}
+	validation.SetCondition(&conditions)
+	conditions.EndStagingConditions()
+	transitioned := past()
+	conditions.List[0].LastTransitionTime = transitioned
+	message := conditions.List[0].Message
+
+	// Test
+	conditions.BeginStagingConditions()
+	validation.SetCondition(&conditions)
+	conditions.EndStagingConditions()
+
+	// Validation
+	g.Expect(validation.Items()).To(gomega.Equal([]string{
+		"spec.hosts(0): NotFound Host/ns1/h1",
+		"spec.tls(a;b): NotFound Secret/ns1/s1",
+	}))
+	g.Expect(len(conditions.List)).To(gomega.Equal(1))
+	g.Expect(conditions.List[0].Message).To(gomega.Equal(message))
+	g.Expect(conditions.List[0].LastTransitionTime).To(gomega.Equal(transitioned))
+}
+
+type _Provider struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+	Status          struct {
+		Conditions condition.Conditions `json:"conditions"`
+	} `json:"status"`
+}
+
+func (t *_Provider) DeepCopyObject() runtime.Object {
+	return t
+}
+
+//
+// Fake client.
+// Objects are stored by name.
+type _Client struct {
+	objects   map[string]runtime.Object
+	updated   int
+	status    int
+	conflicts int
+}
+
+func (c *_Client) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	stored, found := c.objects[key.Name]
+	if !found {
+		return errors.NewNotFound(schema.GroupResource{}, key.Name)
+	}
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored).Elem())
+	return nil
+}
+
+func (c *_Client) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	return nil
+}
+
+func (c *_Client) Create(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+func (c *_Client) Delete(ctx context.Context, obj runtime.Object, opts ...client.DeleteOptionFunc) error {
+	return nil
+}
+
+func (c *_Client) Update(ctx context.Context, obj runtime.Object) error {
+	if c.conflicts > 0 {
+		c.conflicts--
+		return errors.NewConflict(schema.GroupResource{}, "", nil)
+	}
+	c.updated++
+	object, _ := apimeta.Accessor(obj)
+	c.objects[object.GetName()] = obj
+	return nil
+}
+
+func (c *_Client) Status() client.StatusWriter {
+	return &_StatusWriter{client: c}
+}
+
+type _StatusWriter struct {
+	client *_Client
+}
+
+func (w *_StatusWriter) Update(ctx context.Context, obj runtime.Object) error {
+	w.client.status++
+	return nil
+}
+
+func TestProtector(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	m := NewMap(nil)
+	fake := &_Client{
+		objects: map[string]runtime.Object{
+			"s1": &v1.Secret{
+				ObjectMeta: meta.ObjectMeta{
+					Namespace:  "ns0",
+					Name:       "s1",
+					Finalizers: []string{"other"},
+				},
+			},
+		},
+	}
+	m.TrackReleased()
+	protector := Protector{Map: m, Client: fake}
+	thing := &_Owner{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "ns0",
+			Name:      "owner",
+		},
+		SecretRef: &v1.ObjectReference{
+			Namespace: "ns0",
+			Name:      "s1",
+		},
+	}
+	m.Mapper().Create(event.CreateEvent{Meta: thing, Object: thing})
+	owner := m.OwnerOf(thing)
+	other := Owner{Kind: "Thing", Namespace: "ns0", Name: "other"}
+
+	// Test protected.
+	err := protector.ProtectTargets(context.TODO(), thing)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(fake.updated).To(gomega.Equal(1))
+	g.Expect(fake.objects["s1"].(*v1.Secret).Finalizers).To(
+		gomega.Equal([]string{"other", ProtectionFinalizer}))
+
+	// Test unchanged.
+	err = protector.ProtectTargets(context.TODO(), thing)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(fake.updated).To(gomega.Equal(1))
+
+	// Test released (not synced).
+	released := Target{APIVersion: "v1", Kind: "Secret", Namespace: "ns0", Name: "s1"}
+	m.DeleteOwner(owner)
+	err = protector.Release(context.TODO())
+	g.Expect(err).To(gomega.Equal(NotSyncedError{Target: released}))
+	g.Expect(fake.updated).To(gomega.Equal(1))
+	g.Expect(m.Released()).To(gomega.Equal([]Target{released}))
+
+	// Test released (conflict).
+	m.SetSynced()
+	fake.conflicts = 1
+	err = protector.Release(context.TODO())
+	g.Expect(errors.IsConflict(err)).To(gomega.BeTrue())
+	g.Expect(fake.updated).To(gomega.Equal(1))
+	g.Expect(m.Released()).To(gomega.Equal([]Target{released}))
+
+	// Test released.
+	err = protector.Release(context.TODO())
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(fake.updated).To(gomega.Equal(2))
+	g.Expect(fake.objects["s1"].(*v1.Secret).Finalizers).To(gomega.Equal([]string{"other"}))
+	g.Expect(m.Released()).To(gomega.BeEmpty())
+
+	// Test deletion blocked.
+	now := meta.Now()
+	provider := &_Provider{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:         "ns0",
+			Name:              "p1",
+			Finalizers:        []string{ProtectionFinalizer},
+			DeletionTimestamp: &now,
+		},
+	}
+	target := Target{Kind: ToKind(provider), Namespace: "ns0", Name: "p1"}
+	m.Add(owner, target)
+	m.Add(other, target)
+	err = protector.Protect(context.TODO(), provider)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(fake.updated).To(gomega.Equal(2))
+	g.Expect(fake.status).To(gomega.Equal(1))
+	blocked := provider.Status.Conditions.FindCondition(condition.DeletionBlocked)
+	g.Expect(blocked).NotTo(gomega.BeNil())
+	g.Expect(blocked.Message).To(gomega.Equal(
+		"Deletion blocked by 2 owners [Thing/ns0/other,_Owner/ns0/owner]."))
+	g.Expect(provider.Finalizers).To(gomega.Equal([]string{ProtectionFinalizer}))
+
+	// Test deletion blocked (unchanged).
+	transitioned := past()
+	blocked.LastTransitionTime = transitioned
+	encoded, _ := json.Marshal(provider)
+	provider = &_Provider{}
+	err = json.Unmarshal(encoded, provider)
+	g.Expect(err).To(gomega.BeNil())
+	err = protector.Protect(context.TODO(), provider)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(fake.status).To(gomega.Equal(1))
+	blocked = provider.Status.Conditions.FindCondition(condition.DeletionBlocked)
+	g.Expect(blocked.LastTransitionTime).To(gomega.Equal(transitioned))
+}
+
+func TestReleasedNotTracked(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	m := NewMap(nil)
+	owner := Owner{Kind: "Thing", Namespace: "ns0", Name: "owner"}
+	m.Add(owner, Target{Kind: "Secret", Namespace: "ns0", Name: "s1"})
+
+	// Test
+	m.DeleteOwner(owner)
+
+	// Validation
+	g.Expect(m.Released()).To(gomega.BeEmpty())
+	g.Expect(m.released).To(gomega.BeEmpty())
+}
+
+func TestResolverShortCache(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	schemeA := runtime.NewScheme()
+	_ = v1.AddToScheme(schemeA)
+	schemeB := runtime.NewScheme()
+	schemeB.AddKnownTypes(schema.GroupVersion{Group: "example.io", Version: "v1"}, &Secret{})
+	resolver := &Resolver{Scheme: schemeA}
+
+	// Test
+	gvkA := resolver.TagKind("Secret")
+	cached := resolver.TagKind("Secret")
+	resolver.Scheme = schemeB
+	gvkB := resolver.TagKind("Secret")
+
+	// Validation
+	g.Expect(gvkA).To(gomega.Equal(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}))
+	g.Expect(cached).To(gomega.Equal(gvkA))
+	g.Expect(gvkB).To(gomega.Equal(schema.GroupVersionKind{Group: "example.io", Version: "v1", Kind: "Secret"}))
+	g.Expect(len(resolver.shortKinds)).To(gomega.Equal(2))
+}
+
+func BenchmarkTagKind(b *testing.B) {
+	resolver := &Resolver{}
+	for i := 0; i < b.N; i++ {
+		resolver.TagKind("Secret")
+	}
+}
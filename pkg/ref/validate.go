@@ -0,0 +1,192 @@
+package ref
+
+import (
+	"context"
+	"fmt"
+	"github.com/jortel/controller/pkg/condition"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strings"
+)
+
+// Replaces characters reserved by condition items.
+var itemPath = strings.NewReplacer("[", "(", "]", ")", ",", ";")
+
+// Reasons
+const (
+	NotFound  = "NotFound"
+	Forbidden = "Forbidden"
+	WrongKind = "WrongKind"
+	NotValid  = "NotValid"
+)
+
+//
+// Ref validator.
+// Resolves each `ref` tagged reference of an owner using
+// a client (or cache) to detect dangling references.
+//
+// Example:
+//     validator := ref.Validator{Client: r.Client}
+//     validation, err := validator.Validate(ctx, thing)
+//     if err != nil {
+//         return err
+//     }
+//     validation.SetCondition(&thing.Status.Conditions)
+//
+type Validator struct {
+	// A client (or cache).
+	Client client.Reader
+	// The kind resolver.
+	Resolver *Resolver
+}
+
+//
+// The result of a validation.
+type Validation struct {
+	// Refs to targets that do not exist.
+	Missing []Ref
+	// Refs to targets that may not be read.
+	Forbidden []Ref
+	// Refs to targets of an unknown kind or a kind
+	// other than declared by the tag.
+	WrongKind []Ref
+}
+
+//
+// Validate the refs of the owner.
+// Errors other than NotFound and Forbidden are returned.
+func (r *Validator) Validate(ctx context.Context, owner interface{}) (Validation, error) {
+	validation := Validation{}
+	extractor := Extractor{Resolver: r.resolver()}
+	for _, ref := range extractor.Extract(owner) {
+		reason, err := r.Check(ctx, ref)
+		if err != nil {
+			return validation, err
+		}
+		switch reason {
+		case NotFound:
+			validation.Missing = append(validation.Missing, ref)
+		case Forbidden:
+			validation.Forbidden = append(validation.Forbidden, ref)
+		case WrongKind:
+			validation.WrongKind = append(validation.WrongKind, ref)
+		}
+	}
+
+	return validation, nil
+}
+
+//
+// Check the ref.
+// Returns the reason the ref is not valid.
+func (r *Validator) Check(ctx context.Context, ref Ref) (string, error) {
+	if reason := r.CheckKind(ref); reason != "" {
+		return reason, nil
+	}
+	target := ref.Target
+	gvk := r.resolver().RefKind(target.APIVersion, target.Kind)
+	object, err := r.resolver().scheme().New(gvk)
+	if err != nil {
+		return WrongKind, nil
+	}
+	key := client.ObjectKey{
+		Namespace: target.Namespace,
+		Name:      target.Name,
+	}
+	err = r.Client.Get(ctx, key, object)
+	switch {
+	case err == nil:
+		return "", nil
+	case errors.IsNotFound(err):
+		return NotFound, nil
+	case errors.IsForbidden(err):
+		return Forbidden, nil
+	case meta.IsNoMatchError(err):
+		return WrongKind, nil
+	}
+
+	return "", err
+}
+
+//
+// Check the kind of the ref.
+// The kind must be known and match the kind declared by the tag.
+// The target is not read.
+// Returns the reason the ref is not valid.
+func (r *Validator) CheckKind(ref Ref) string {
+	target := ref.Target
+	gvk := r.resolver().RefKind(target.APIVersion, target.Kind)
+	if !ref.Declared.Empty() && ref.Declared.GroupKind() != gvk.GroupKind() {
+		return WrongKind
+	}
+	if !r.resolver().scheme().Recognizes(gvk) {
+		return WrongKind
+	}
+
+	return ""
+}
+
+//
+// The kind resolver.
+func (r *Validator) resolver() *Resolver {
+	if r.Resolver != nil {
+		return r.Resolver
+	}
+
+	return defaultResolver
+}
+
+//
+// All refs are valid.
+func (r *Validation) Valid() bool {
+	return len(r.Missing) == 0 &&
+		len(r.Forbidden) == 0 &&
+		len(r.WrongKind) == 0
+}
+
+//
+// Describe the invalid refs.
+// Format: <path>: <reason> <kind>/<namespace>/<name>
+// The [] and commas in paths are replaced by () and ; so that
+// items may be parsed from the condition message.
+// Example: spec.hosts(0): NotFound Host/ns1/h1
+func (r *Validation) Items() []string {
+	list := []string{}
+	add := func(reason string, refs []Ref) {
+		for _, ref := range refs {
+			target := ref.Target
+			item := fmt.Sprintf("%s/%s", target.Kind, target.Name)
+			if target.Namespace != "" {
+				item = fmt.Sprintf("%s/%s/%s", target.Kind, target.Namespace, target.Name)
+			}
+			path := itemPath.Replace(ref.Path)
+			list = append(list, fmt.Sprintf("%s: %s %s", path, reason, item))
+		}
+	}
+	add(NotFound, r.Missing)
+	add(Forbidden, r.Forbidden)
+	add(WrongKind, r.WrongKind)
+	sort.Strings(list)
+
+	return list
+}
+
+//
+// Set the standard `InvalidRef` condition.
+// The condition is deleted when all refs are valid.
+func (r *Validation) SetCondition(conditions *condition.Conditions) {
+	if r.Valid() {
+		conditions.DeleteCondition(condition.InvalidRef)
+		return
+	}
+	conditions.SetCondition(condition.Condition{
+		Type:     condition.InvalidRef,
+		Status:   condition.True,
+		Reason:   NotValid,
+		Category: condition.Error,
+		Message:  "The referenced resources [] are not valid.",
+		Items:    r.Items(),
+	})
+}
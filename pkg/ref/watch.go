@@ -0,0 +1,62 @@
+package ref
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+)
+
+//
+// Watch a kind of resource.
+// Adapts `Controller.Watch()` which cannot be referenced directly.
+//
+// Example:
+//     func(kind runtime.Object, h handler.EventHandler, p ref.Predicate) error {
+//         return cnt.Watch(&source.Kind{Type: kind}, h, p)
+//     }
+type WatchFunc func(kind runtime.Object, h handler.EventHandler, p Predicate) error
+
+//
+// Build the owner predicate.
+func (r *RefMap) Predicate() *MapPredicate {
+	return &MapPredicate{Mapper: r.Mapper()}
+}
+
+//
+// Build the target event handler.
+func (r *RefMap) Handler() *EventHandler {
+	return &EventHandler{Map: r}
+}
+
+//
+// Register the owner and target watches.
+// The owner is watched using the map predicate so the map is
+// updated on owner events. Each target kind is watched using the
+// map event handler so that mapped owners are enqueued.
+// The map owner-kind filter is set to the owner kind when not set.
+//
+// Example:
+//     refMap := ref.NewMap(&Resource{})
+//     err = refMap.Watch(
+//         func(kind runtime.Object, h handler.EventHandler, p ref.Predicate) error {
+//             return cnt.Watch(&source.Kind{Type: kind}, h, p)
+//         },
+//         &Resource{},
+//         &v1.Secret{},
+//         &v1.ConfigMap{})
+func (r *RefMap) Watch(watch WatchFunc, owner runtime.Object, targets ...runtime.Object) error {
+	if r.OwnerKind.Empty() {
+		r.OwnerKind = r.resolver().ObjectKind(owner).GroupKind()
+	}
+	err := watch(owner, &handler.EnqueueRequestForObject{}, r.Predicate())
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		err = watch(target, r.Handler(), &passThrough{})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/appscode/jsonpatch"
+	"github.com/jortel/controller/pkg/condition"
+	admission "k8s.io/api/admission/v1beta1"
+	"net/http"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"sort"
+)
+
+// Condition handler modes.
+const (
+	// Reject changes to conditions.
+	Reject = "Reject"
+	// Strip (revert) changes to conditions.
+	Strip = "Strip"
+)
+
+// Paths.
+const (
+	StatusPath     = "/status"
+	ConditionsPath = "/status/conditions"
+)
+
+//
+// Condition protection (admission) handler.
+// Protects `status.conditions` from changes by users other
+// than the configured (controller) service accounts.
+// Changes made by other users are rejected or stripped
+// based on the `Mode`. Changes made by permitted users are
+// validated:
+//   - Status and Category must be known values.
+//   - Durable conditions must not be removed.
+// Implements the admission.Handler interface.
+//
+// Example:
+//     handler := &webhook.ConditionHandler{
+//         Users: []string{
+//             "system:serviceaccount:thing-system:thing-controller",
+//         },
+//         Mode: webhook.Strip,
+//     }
+//
+type ConditionHandler struct {
+	// Users permitted to change conditions.
+	// Format: system:serviceaccount:<namespace>:<name>
+	Users []string
+	// Handling of changes by other users: Reject|Strip.
+	// Default: Reject.
+	Mode string
+}
+
+//
+// The conditions found in an object.
+type conditions struct {
+	Status struct {
+		Conditions *json.RawMessage `json:"conditions"`
+	} `json:"status"`
+}
+
+//
+// Handle the admission request.
+func (h *ConditionHandler) Handle(ctx context.Context, request types.Request) types.Response {
+	ar := request.AdmissionRequest
+	if ar.Operation == admission.Delete {
+		return Allowed()
+	}
+	old, err := h.decode(ar.OldObject.Raw)
+	if err != nil {
+		return Errored(http.StatusBadRequest, err)
+	}
+	new, err := h.decode(ar.Object.Raw)
+	if err != nil {
+		return Errored(http.StatusBadRequest, err)
+	}
+	if h.permitted(ar.UserInfo.Username) {
+		reasons := h.validate(old, new)
+		if len(reasons) > 0 {
+			return Denied(reasons...)
+		}
+		return Allowed()
+	}
+	oldList, err := h.list(old)
+	if err != nil {
+		return Errored(http.StatusBadRequest, err)
+	}
+	newList, err := h.list(new)
+	if err != nil {
+		return Errored(http.StatusBadRequest, err)
+	}
+	if reflect.DeepEqual(oldList, newList) {
+		return Allowed()
+	}
+	if h.Mode != Strip {
+		return Denied(
+			fmt.Sprintf(
+				"user: %s not permitted to change %s.",
+				ar.UserInfo.Username,
+				ConditionsPath))
+	}
+	if old == nil {
+		return Patched(jsonpatch.NewPatch("remove", ConditionsPath, nil))
+	}
+	if new == nil {
+		if !h.hasStatus(ar.Object.Raw) {
+			return Patched(
+				jsonpatch.NewPatch(
+					"add",
+					StatusPath,
+					map[string]interface{}{"conditions": old}))
+		}
+		return Patched(jsonpatch.NewPatch("add", ConditionsPath, old))
+	}
+
+	return Patched(jsonpatch.NewPatch("replace", ConditionsPath, old))
+}
+
+//
+// Validate conditions.
+// Returns a list of reasons (problems).
+func (h *ConditionHandler) validate(old, new *json.RawMessage) []string {
+	reasons := []string{}
+	newList, err := h.list(new)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	oldList, err := h.list(old)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	known := func(list []string, value string) bool {
+		for _, s := range list {
+			if s == value {
+				return true
+			}
+		}
+		return false
+	}
+	found := map[string]bool{}
+	for _, cnd := range newList {
+		found[cnd.Type] = true
+		if !known(condition.Statuses, cnd.Status) {
+			reasons = append(
+				reasons,
+				fmt.Sprintf(
+					"condition: %s status: '%s' must be one of: %v.",
+					cnd.Type,
+					cnd.Status,
+					condition.Statuses))
+		}
+		if !known(condition.Categories, cnd.Category) {
+			reasons = append(
+				reasons,
+				fmt.Sprintf(
+					"condition: %s category: '%s' must be one of: %v.",
+					cnd.Type,
+					cnd.Category,
+					condition.Categories))
+		}
+	}
+	removed := []string{}
+	for _, cnd := range oldList {
+		if cnd.Durable && !found[cnd.Type] {
+			removed = append(removed, cnd.Type)
+		}
+	}
+	if len(removed) > 0 {
+		sort.Strings(removed)
+		reasons = append(
+			reasons,
+			fmt.Sprintf("durable conditions: %v must not be removed.", removed))
+	}
+
+	return reasons
+}
+
+//
+// Get whether the user is permitted to change conditions.
+func (h *ConditionHandler) permitted(user string) bool {
+	for _, u := range h.Users {
+		if u == user {
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+// Decode the (raw) conditions in the object.
+// Returns nil when the object has no conditions.
+func (h *ConditionHandler) decode(raw []byte) (*json.RawMessage, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	object := conditions{}
+	err := json.Unmarshal(raw, &object)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.Status.Conditions, nil
+}
+
+//
+// Get whether the object has a `status`.
+func (h *ConditionHandler) hasStatus(raw []byte) bool {
+	object := map[string]json.RawMessage{}
+	err := json.Unmarshal(raw, &object)
+	if err != nil {
+		return false
+	}
+	status, found := object["status"]
+
+	return found && string(status) != "null"
+}
+
+//
+// Decode the list of conditions.
+func (h *ConditionHandler) list(raw *json.RawMessage) ([]condition.Condition, error) {
+	list := []condition.Condition{}
+	if raw == nil {
+		return list, nil
+	}
+	err := json.Unmarshal(*raw, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/onsi/gomega"
+	admission "k8s.io/api/admission/v1beta1"
+	auth "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"testing"
+)
+
+const (
+	controllerSA = "system:serviceaccount:thing:controller"
+)
+
+func conditionRequest(user, old, new string) types.Request {
+	return types.Request{
+		AdmissionRequest: &admission.AdmissionRequest{
+			Operation: admission.Update,
+			UserInfo:  auth.UserInfo{Username: user},
+			OldObject: runtime.RawExtension{Raw: []byte(old)},
+			Object:    runtime.RawExtension{Raw: []byte(new)},
+		},
+	}
+}
+
+func TestConditionHandler(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	handler := &ConditionHandler{
+		Users: []string{controllerSA},
+	}
+	old := `{"status":{"conditions":[{"type":"A","status":"True","category":"Error"}]}}`
+	new := `{"status":{"conditions":[{"type":"A","status":"False","category":"Error"}]}}`
+
+	// Test permitted.
+	response := handler.Handle(context.TODO(), conditionRequest(controllerSA, old, new))
+	g.Expect(response.Response.Allowed).To(gomega.BeTrue())
+
+	// Test not permitted (unchanged).
+	response = handler.Handle(context.TODO(), conditionRequest("joe", old, old))
+	g.Expect(response.Response.Allowed).To(gomega.BeTrue())
+
+	// Test not permitted (rejected).
+	response = handler.Handle(context.TODO(), conditionRequest("joe", old, new))
+	g.Expect(response.Response.Allowed).To(gomega.BeFalse())
+
+	// Test not permitted (stripped).
+	handler.Mode = Strip
+	response = handler.Handle(context.TODO(), conditionRequest("joe", old, new))
+	g.Expect(response.Response.Allowed).To(gomega.BeTrue())
+	g.Expect(len(response.Patches)).To(gomega.Equal(1))
+	g.Expect(response.Patches[0].Operation).To(gomega.Equal("replace"))
+	g.Expect(response.Patches[0].Path).To(gomega.Equal(ConditionsPath))
+
+	// Test not permitted (added and stripped).
+	response = handler.Handle(context.TODO(), conditionRequest("joe", `{}`, new))
+	g.Expect(response.Response.Allowed).To(gomega.BeTrue())
+	g.Expect(response.Patches[0].Operation).To(gomega.Equal("remove"))
+
+	// Test not permitted (conditions removed and stripped).
+	response = handler.Handle(context.TODO(), conditionRequest("joe", old, `{"status":{}}`))
+	g.Expect(response.Response.Allowed).To(gomega.BeTrue())
+	g.Expect(response.Patches[0].Operation).To(gomega.Equal("add"))
+	g.Expect(response.Patches[0].Path).To(gomega.Equal(ConditionsPath))
+
+	// Test not permitted (status removed and stripped).
+	response = handler.Handle(context.TODO(), conditionRequest("joe", old, `{}`))
+	g.Expect(response.Response.Allowed).To(gomega.BeTrue())
+	g.Expect(len(response.Patches)).To(gomega.Equal(1))
+	g.Expect(response.Patches[0].Operation).To(gomega.Equal("add"))
+	g.Expect(response.Patches[0].Path).To(gomega.Equal(StatusPath))
+	patched, err := json.Marshal(response.Patches[0].Value)
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(patched).To(gomega.MatchJSON(
+		`{"conditions":[{"type":"A","status":"True","category":"Error"}]}`))
+}
+
+func TestConditionHandlerValidation(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	handler := &ConditionHandler{
+		Users: []string{controllerSA},
+	}
+	old := `{"status":{"conditions":[{"type":"A","status":"True","category":"Error","durable":true}]}}`
+
+	// Test unknown status and category.
+	new := `{"status":{"conditions":[{"type":"A","status":"Maybe","category":"Bad","durable":true}]}}`
+	response := handler.Handle(context.TODO(), conditionRequest(controllerSA, old, new))
+	g.Expect(response.Response.Allowed).To(gomega.BeFalse())
+	g.Expect(response.Response.Result.Message).To(gomega.ContainSubstring("status: 'Maybe'"))
+	g.Expect(response.Response.Result.Message).To(gomega.ContainSubstring("category: 'Bad'"))
+
+	// Test durable removed.
+	new = `{"status":{"conditions":[]}}`
+	response = handler.Handle(context.TODO(), conditionRequest(controllerSA, old, new))
+	g.Expect(response.Response.Allowed).To(gomega.BeFalse())
+	g.Expect(response.Response.Result.Message).To(gomega.ContainSubstring("durable conditions: [A]"))
+}
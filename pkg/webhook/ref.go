@@ -0,0 +1,199 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/jortel/controller/pkg/ref"
+	admission "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"net/http"
+	"reflect"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+//
+// Ref target check.
+// Returns a (denial) message when the ref is not valid.
+type RefCheck func(ctx context.Context, owner runtime.Object, found ref.Ref) (string, error)
+
+//
+// Ref validation (admission) handler.
+// The `ref` tagged references found in the (incoming) object
+// are validated using the `Checks`. Refs unchanged by an update
+// are not validated. Denial messages are prefixed with the field
+// path. Implements the admission.Handler interface.
+//
+// Example:
+//     handler := &webhook.RefHandler{
+//         Object: &Thing{},
+//         Checks: []webhook.RefCheck{
+//             webhook.KindCheck(),
+//             webhook.NamespaceCheck("shared"),
+//             webhook.ExistsCheck(mgr.GetClient()),
+//         },
+//     }
+//
+type RefHandler struct {
+	// The kind of object (prototype) handled.
+	Object runtime.Object
+	// Target checks.
+	// Default: KindCheck().
+	Checks []RefCheck
+}
+
+//
+// Handle the admission request.
+func (h *RefHandler) Handle(ctx context.Context, request types.Request) types.Response {
+	ar := request.AdmissionRequest
+	if ar.Operation == admission.Delete {
+		return Allowed()
+	}
+	object, err := h.decode(ar.Object.Raw, ar.Namespace)
+	if err != nil {
+		return Errored(http.StatusBadRequest, err)
+	}
+	unchanged := map[ref.Ref]bool{}
+	if ar.Operation == admission.Update && len(ar.OldObject.Raw) > 0 {
+		old, err := h.decode(ar.OldObject.Raw, ar.Namespace)
+		if err != nil {
+			return Errored(http.StatusBadRequest, err)
+		}
+		for _, found := range h.extract(old) {
+			unchanged[found] = true
+		}
+	}
+	reasons := []string{}
+	for _, found := range h.extract(object) {
+		if unchanged[found] {
+			continue
+		}
+		for _, check := range h.checks() {
+			message, err := check(ctx, object, found)
+			if err != nil {
+				return Errored(http.StatusInternalServerError, err)
+			}
+			if message != "" {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", found.Path, message))
+				break
+			}
+		}
+	}
+	if len(reasons) > 0 {
+		return Denied(reasons...)
+	}
+
+	return Allowed()
+}
+
+//
+// Decode a new object.
+// The namespace is defaulted to the request namespace.
+func (h *RefHandler) decode(raw []byte, namespace string) (runtime.Object, error) {
+	object := reflect.New(reflect.TypeOf(h.Object).Elem()).Interface().(runtime.Object)
+	err := json.Unmarshal(raw, object)
+	if err != nil {
+		return nil, err
+	}
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return nil, err
+	}
+	if accessor.GetNamespace() == "" {
+		accessor.SetNamespace(namespace)
+	}
+
+	return object, nil
+}
+
+//
+// Extract the refs.
+func (h *RefHandler) extract(object runtime.Object) []ref.Ref {
+	extractor := ref.Extractor{}
+	return extractor.Extract(object)
+}
+
+//
+// The target checks.
+func (h *RefHandler) checks() []RefCheck {
+	if len(h.Checks) > 0 {
+		return h.Checks
+	}
+
+	return []RefCheck{KindCheck()}
+}
+
+//
+// Check the target kind is known and matches
+// the kind declared by the tag.
+func KindCheck() RefCheck {
+	return func(ctx context.Context, owner runtime.Object, found ref.Ref) (string, error) {
+		validator := ref.Validator{}
+		if validator.CheckKind(found) != "" {
+			return fmt.Sprintf("%s is not a valid kind.", describe(found)), nil
+		}
+
+		return "", nil
+	}
+}
+
+//
+// Check the target is in an allowed namespace.
+// The owner namespace is always allowed.
+// Cluster-scoped targets are not checked.
+func NamespaceCheck(allowed ...string) RefCheck {
+	return func(ctx context.Context, owner runtime.Object, found ref.Ref) (string, error) {
+		namespace := found.Target.Namespace
+		if namespace == "" {
+			return "", nil
+		}
+		if accessor, err := meta.Accessor(owner); err == nil {
+			if namespace == accessor.GetNamespace() {
+				return "", nil
+			}
+		}
+		for _, ns := range allowed {
+			if namespace == ns {
+				return "", nil
+			}
+		}
+
+		return fmt.Sprintf("%s namespace not allowed.", describe(found)), nil
+	}
+}
+
+//
+// Check the target exists (using the client).
+func ExistsCheck(reader client.Reader) RefCheck {
+	return func(ctx context.Context, owner runtime.Object, found ref.Ref) (string, error) {
+		validator := ref.Validator{Client: reader}
+		reason, err := validator.Check(ctx, found)
+		if err != nil {
+			return "", err
+		}
+		switch reason {
+		case ref.NotFound:
+			return fmt.Sprintf("%s not found.", describe(found)), nil
+		case ref.Forbidden:
+			return fmt.Sprintf("%s access forbidden.", describe(found)), nil
+		case ref.WrongKind:
+			return fmt.Sprintf("%s is not a valid kind.", describe(found)), nil
+		}
+
+		return "", nil
+	}
+}
+
+//
+// Describe the ref target.
+// Format: <kind> <namespace>/<name>
+func describe(found ref.Ref) string {
+	target := found.Target
+	if target.Namespace == "" {
+		return fmt.Sprintf("%s %s", target.Kind, target.Name)
+	}
+
+	return fmt.Sprintf("%s %s/%s", target.Kind, target.Namespace, target.Name)
+}
@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"context"
+	"github.com/onsi/gomega"
+	admission "k8s.io/api/admission/v1beta1"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"testing"
+)
+
+type _Plan struct {
+	meta.TypeMeta   `json:",inline"`
+	meta.ObjectMeta `json:"metadata,omitempty"`
+	Spec            _PlanSpec `json:"spec"`
+}
+
+type _PlanSpec struct {
+	Secret *v1.ObjectReference `json:"secret" ref:"Secret"`
+	Config *v1.ObjectReference `json:"config" ref:"ConfigMap"`
+}
+
+func (t *_Plan) DeepCopyObject() runtime.Object {
+	return t
+}
+
+//
+// Fake client.
+// Only objects with (existing) names are found.
+type _Client struct {
+	existing map[string]bool
+}
+
+func (c *_Client) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	if c.existing[key.Name] {
+		return nil
+	}
+
+	return errors.NewNotFound(schema.GroupResource{}, key.Name)
+}
+
+func (c *_Client) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	return nil
+}
+
+func refRequest(op admission.Operation, old, new string) types.Request {
+	return types.Request{
+		AdmissionRequest: &admission.AdmissionRequest{
+			Operation: op,
+			Namespace: "ns0",
+			OldObject: runtime.RawExtension{Raw: []byte(old)},
+			Object:    runtime.RawExtension{Raw: []byte(new)},
+		},
+	}
+}
+
+func TestRefHandler(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Setup
+	handler := &RefHandler{
+		Object: &_Plan{},
+		Checks: []RefCheck{
+			KindCheck(),
+			NamespaceCheck("shared"),
+			ExistsCheck(&_Client{existing: map[string]bool{"s1": true, "c1": true}}),
+		},
+	}
+	valid := `{"spec":{"secret":{"namespace":"shared","name":"s1"},"config":{"namespace":"ns0","name":"c1"}}}`
+	missing := `{"spec":{"secret":{"namespace":"ns0","name":"s2"}}}`
+	namespace := `{"spec":{"secret":{"namespace":"other","name":"s1"}}}`
+	kind := `{"spec":{"config":{"kind":"Secret","namespace":"ns0","name":"c1"}}}`
+
+	// Test valid.
+	response := handler.Handle(context.TODO(), refRequest(admission.Create, "", valid))
+	g.Expect(response.Response.Allowed).To(gomega.BeTrue())
+
+	// Test not found.
+	response = handler.Handle(context.TODO(), refRequest(admission.Create, "", missing))
+	g.Expect(response.Response.Allowed).To(gomega.BeFalse())
+	g.Expect(response.Response.Result.Message).To(
+		gomega.Equal("spec.secret: Secret ns0/s2 not found."))
+
+	// Test namespace not allowed.
+	response = handler.Handle(context.TODO(), refRequest(admission.Create, "", namespace))
+	g.Expect(response.Response.Allowed).To(gomega.BeFalse())
+	g.Expect(response.Response.Result.Message).To(
+		gomega.Equal("spec.secret: Secret other/s1 namespace not allowed."))
+
+	// Test wrong kind.
+	response = handler.Handle(context.TODO(), refRequest(admission.Create, "", kind))
+	g.Expect(response.Response.Allowed).To(gomega.BeFalse())
+	g.Expect(response.Response.Result.Message).To(
+		gomega.Equal("spec.config: Secret ns0/c1 is not a valid kind."))
+
+	// Test unchanged (not validated).
+	response = handler.Handle(context.TODO(), refRequest(admission.Update, missing, missing))
+	g.Expect(response.Response.Allowed).To(gomega.BeTrue())
+
+	// Test delete.
+	response = handler.Handle(context.TODO(), refRequest(admission.Delete, "", missing))
+	g.Expect(response.Response.Allowed).To(gomega.BeTrue())
+
+	// Test malformed.
+	response = handler.Handle(context.TODO(), refRequest(admission.Create, "", "{"))
+	g.Expect(response.Response.Allowed).To(gomega.BeFalse())
+	g.Expect(response.Response.Result.Code).To(gomega.Equal(int32(400)))
+}
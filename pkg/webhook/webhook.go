@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"github.com/appscode/jsonpatch"
+	admission "k8s.io/api/admission/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+	"strings"
+)
+
+//
+// Build an `allowed` response.
+func Allowed() types.Response {
+	return types.Response{
+		Response: &admission.AdmissionResponse{
+			Allowed: true,
+			Result: &meta.Status{
+				Code: http.StatusOK,
+			},
+		},
+	}
+}
+
+//
+// Build a `denied` response.
+func Denied(reason ...string) types.Response {
+	return types.Response{
+		Response: &admission.AdmissionResponse{
+			Allowed: false,
+			Result: &meta.Status{
+				Code:    http.StatusForbidden,
+				Reason:  meta.StatusReasonForbidden,
+				Message: strings.Join(reason, "; "),
+			},
+		},
+	}
+}
+
+//
+// Build an `errored` response.
+func Errored(code int32, err error) types.Response {
+	return types.Response{
+		Response: &admission.AdmissionResponse{
+			Allowed: false,
+			Result: &meta.Status{
+				Code:    code,
+				Message: err.Error(),
+			},
+		},
+	}
+}
+
+//
+// Build a `patched` response.
+func Patched(patches ...jsonpatch.JsonPatchOperation) types.Response {
+	response := Allowed()
+	response.Patches = patches
+	if len(patches) > 0 {
+		pt := admission.PatchTypeJSONPatch
+		response.Response.PatchType = &pt
+	}
+
+	return response
+}